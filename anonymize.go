@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/mock"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// runAnonymize implements the anonymize subcommand:
+//
+//	anonymize [-locale nl-NL] [-out fixtures/outlets.json] captured.pb
+//
+// The input is a protobuf or protojson OutletDetailsResponse. The output is
+// protojson when -out ends in .json or is omitted (stdout), protobuf otherwise.
+func runAnonymize(args []string) error {
+	flags := flag.NewFlagSet("anonymize", flag.ExitOnError)
+	locale := flags.String("locale", mock.DefaultLocale, "locale the replacement names and addresses are drawn from")
+	out := flags.String("out", "", "output file, stdout when empty")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s anonymize [flags] <captured OutletDetailsResponse>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	response, err := readOutletsFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	mock.NewAnonymizer(*locale).Response(response)
+
+	if *out == "" || strings.EqualFold(filepath.Ext(*out), ".json") {
+		data, err := protojson.MarshalOptions{Multiline: true}.Marshal(response)
+		if err != nil {
+			return err
+		}
+		if *out == "" {
+			_, err = os.Stdout.Write(append(data, '\n'))
+			return err
+		}
+		return os.WriteFile(*out, append(data, '\n'), 0644)
+	}
+	data, err := proto.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out, data, 0644)
+}
+
+// readOutletsFile decodes an OutletDetailsResponse, as protojson for .json
+// files and as protobuf otherwise
+func readOutletsFile(path string) (*pb.OutletDetailsResponse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.OutletDetailsResponse{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = protojson.Unmarshal(data, response)
+	} else {
+		err = proto.Unmarshal(data, response)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return response, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"srv-eazle-advise-mock/pkg/fixtures"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/mock"
+	"srv-eazle-advise-mock/pkg/mockserver"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// runGenerate implements the generate subcommand, which writes a dataset to a
+// directory without starting the server:
+//
+//	generate [-count 100] [-seed 42] [-format protojson] [-settings settings.json] [-out dataset]
+func runGenerate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	count := flags.Int("count", 100, "number of outlets to generate")
+	seed := flags.Int64("seed", 0, "random seed, 0 picks one and prints it")
+	format := flags.String("format", "protojson", "output format: protobuf, protojson, ndjson or csv")
+	settingsFlag := flags.String("settings", "", "MockSettings as inline JSON or a JSON file, unset fields keep the server defaults")
+	locale := flags.String("locale", mock.DefaultLocale, "locale of the generated data, overrides settings")
+	nowFlag := flags.String("now", "", "reference time the generated dates are relative to (RFC 3339 or YYYY-MM-DD), default today 00:00 UTC")
+	out := flags.String("out", "dataset", "output directory")
+	flags.Parse(args)
+
+	settings := mockserver.DefaultSettings(*locale)
+	if *settingsFlag != "" {
+		if err := applySettings(&settings, *settingsFlag); err != nil {
+			return err
+		}
+	}
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "locale" {
+			settings.Locale = *locale
+		}
+	})
+
+	now := time.Now().UTC().Truncate(24 * time.Hour)
+	if *nowFlag != "" {
+		parsed, err := parseTime(*nowFlag)
+		if err != nil {
+			return err
+		}
+		now = parsed
+	}
+	if *seed == 0 {
+		*seed = rand.Int63()
+	}
+
+	outlets := mock.GenerateDataset(*count, settings, *seed, now)
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		return err
+	}
+
+	var err error
+	switch *format {
+	case "protobuf":
+		err = writeGenerated(filepath.Join(*out, "outlets.pb"), func() ([]byte, error) {
+			return proto.Marshal(&pb.OutletDetailsResponse{Details: outlets})
+		})
+	case "protojson":
+		err = writeGenerated(filepath.Join(*out, "outlets.json"), func() ([]byte, error) {
+			data, err := protojson.MarshalOptions{Multiline: true}.Marshal(&pb.OutletDetailsResponse{Details: outlets})
+			return append(data, '\n'), err
+		})
+	case "ndjson":
+		err = writeNDJSON(filepath.Join(*out, "outlets.ndjson"), outlets)
+	case "csv":
+		err = fixtures.WriteCSV(*out, outlets)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Generated %d outlets to %s (format %s, seed %d, now %s)\n", *count, *out, *format, *seed, now.Format(time.RFC3339))
+	return nil
+}
+
+// applySettings overrides settings with the fields set in value, which is
+// either inline JSON or the path of a JSON file
+func applySettings(settings *mock.MockSettings, value string) error {
+	data := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		var err error
+		if data, err = os.ReadFile(value); err != nil {
+			return err
+		}
+	}
+	// Decoding into the defaults keeps every field the JSON doesn't mention
+	if err := json.Unmarshal(data, settings); err != nil {
+		return fmt.Errorf("invalid settings: %w", err)
+	}
+	return nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, use RFC 3339 or YYYY-MM-DD", value)
+	}
+	return parsed, nil
+}
+
+func writeGenerated(path string, encode func() ([]byte, error)) error {
+	data, err := encode()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// writeNDJSON writes one protojson OutletDetails per line
+func writeNDJSON(path string, outlets []*pb.OutletDetails) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, outlet := range outlets {
+		data, err := protojson.Marshal(outlet)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -1,171 +1,209 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
-	"strconv"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
-	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/fixtures"
 	"srv-eazle-advise-mock/pkg/mock"
+	"srv-eazle-advise-mock/pkg/mockserver"
+	"srv-eazle-advise-mock/pkg/proxy"
+	"srv-eazle-advise-mock/pkg/store"
 
-	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/proto"
+	"google.golang.org/grpc"
 )
 
 const (
-	SECRET_KEY = "eazle-secret-2025"
+	SECRET_KEY = mockserver.DefaultSecretKey
 	PORT       = "8080"
 )
 
-func main() {
-	http.HandleFunc("/outlets", handleOutletDetails)
-	http.HandleFunc("/health", handleHealth)
-
-	fmt.Printf("Server starting on port %s\n", PORT)
-	fmt.Printf("Secret key required: %s\n", SECRET_KEY)
-	log.Fatal(http.ListenAndServe(":"+PORT, nil))
-}
-
-func handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
-}
+var (
+	statefulMode = flag.Bool("stateful", false, "serve a persistent outlet universe instead of generating outlets per request")
+	universeSize = flag.Int("outlets", 100, "number of outlets to materialize in stateful mode")
+	snapshotPath = flag.String("snapshot", "", "file the stateful universe is saved to on demand and on shutdown (.json for protojson, protobuf otherwise)")
+	restorePath  = flag.String("restore", "", "snapshot file to restore the stateful universe from at startup")
+	syncChanges  = flag.Float64("sync-changes-per-minute", 0, "random changes made to the stateful universe per minute for /sync/outlets to pick up, 0 disables them")
+	fixturesDir  = flag.String("fixtures", "", "directory with outlet fixtures (*.json, outlets.csv, contacts.csv, products.csv)")
+	localesDir   = flag.String("locales", "", "directory with additional or replacement locale data files (<code>.json)")
+	localeFlag   = flag.String("locale", mock.DefaultLocale, "locale used when a request has no Accept-Language header or locale setting")
+	faultsFile   = flag.String("faults", "", "JSON file with fault injection rules applied at startup")
+	latencyFile  = flag.String("latency", "", "JSON file with latency profiles and their route bindings")
+	scenarioFile = flag.String("scenarios", "", "JSON file with scenario timelines applied at startup")
+	rateLimits   = flag.String("ratelimits", "", "JSON file with token bucket rate limits per API key and route")
+	mappingsDir  = flag.String("mappings", "", "directory with stub mappings (*.json) that take precedence over the generators")
+	proxyURL     = flag.String("proxy", "", "upstream URL that requests not matching a stub mapping are forwarded to")
+	recordDir    = flag.String("record", "", "directory proxied exchanges are recorded to as stub mappings, replay them with -mappings")
+	grpcPort     = flag.String("grpc-port", "", "port OutletService is served on over gRPC, e.g. 9090, disabled when empty")
+	cacheEntries = flag.Int("cache-entries", 1000, "number of seeded (X-Seed) responses kept in the response cache, 0 for no limit (both cache limits 0 disable it)")
+	cacheSizeMB  = flag.Int("cache-size-mb", 256, "total size of the response cache in MB, 0 for no limit")
+	maxOutlets   = flag.Int("max-outlets", mockserver.DefaultMaxOutlets, "largest X-Outlet-Num a request may ask for, larger requests get a 413")
+	journalSize  = flag.Int("journal-size", 1000, "number of requests kept in memory for the verification API")
+	journalFile  = flag.String("journal-file", "", "file every received request is appended to as JSON Lines, e.g. requests.jsonl")
+)
 
-func handleOutletDetails(w http.ResponseWriter, r *http.Request) {
-	// Check secret key
-	if !validateSecretKey(r) {
-		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
-		return
+func main() {
+	// Subcommands work on files and exit without starting the server
+	if len(os.Args) > 1 {
+		var run func([]string) error
+		switch os.Args[1] {
+		case "anonymize":
+			run = runAnonymize
+		case "generate":
+			run = runGenerate
+		}
+		if run != nil {
+			if err := run(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
 	}
 
-	// Handle delay if specified
-	handleDelay(r)
+	flag.Parse()
 
-	// Get outlet ID from query parameter
-	outletID := r.URL.Query().Get("outlet_id")
-	if outletID == "" {
-		outletID = "outlet-001" // Default outlet
+	if *localesDir != "" {
+		if err := mock.LoadLocales(*localesDir); err != nil {
+			log.Fatal(err)
+		}
 	}
-
-	// Generate mock outlet data with configurable settings
-	// Set default settings
-	settings := mock.MockSettings{
-		AverageNotesList:               30,
-		AverageVisitHistory:            96,
-		AverageNumberOfOrders:          90,
-		AverageOrderItemsPerOrder:      20,
-		AverageTopProductsInStatistics: 6,
-		AverageOutletsNearby:           10,
-		AverageAssetList:               6,
-		AverageChecklist:               18,
-		AverageNews:                    22,
+	fmt.Printf("Available locales: %s\n", strings.Join(mock.Locales(), ", "))
+
+	settings := mockserver.DefaultSettings(*localeFlag)
+	opts := mockserver.Options{
+		SecretKey:        SECRET_KEY,
+		Settings:         &settings,
+		Stateful:         *statefulMode,
+		Outlets:          *universeSize,
+		SnapshotPath:     *snapshotPath,
+		CacheEntries:     *cacheEntries,
+		CacheBytes:       int64(*cacheSizeMB) << 20,
+		MaxOutlets:       *maxOutlets,
+		JournalSize:      *journalSize,
+		ChangesPerMinute: *syncChanges,
 	}
 
-	// If request body exists, try to decode custom settings
-	if r.Body != nil {
-		decoder := json.NewDecoder(r.Body)
-		var customSettings mock.MockSettingsOptional
-		if err := decoder.Decode(&customSettings); err == nil {
-			if customSettings.AverageNotesList != nil {
-				settings.AverageNotesList = *customSettings.AverageNotesList
-			}
-			if customSettings.AverageVisitHistory != nil {
-				settings.AverageVisitHistory = *customSettings.AverageVisitHistory
-			}
-			if customSettings.AverageNumberOfOrders != nil {
-				settings.AverageNumberOfOrders = *customSettings.AverageNumberOfOrders
-			}
-			if customSettings.AverageOrderItemsPerOrder != nil {
-				settings.AverageOrderItemsPerOrder = *customSettings.AverageOrderItemsPerOrder
-			}
-			if customSettings.AverageTopProductsInStatistics != nil {
-				settings.AverageTopProductsInStatistics = *customSettings.AverageTopProductsInStatistics
-			}
-			if customSettings.AverageOutletsNearby != nil {
-				settings.AverageOutletsNearby = *customSettings.AverageOutletsNearby
-			}
-			if customSettings.AverageAssetList != nil {
-				settings.AverageAssetList = *customSettings.AverageAssetList
-			}
-			if customSettings.AverageChecklist != nil {
-				settings.AverageChecklist = *customSettings.AverageChecklist
-			}
-			if customSettings.AverageNews != nil {
-				settings.AverageNews = *customSettings.AverageNews
-			}
+	if *fixturesDir != "" {
+		loaded, err := fixtures.Load(*fixturesDir)
+		if err != nil {
+			log.Fatal(err)
 		}
+		opts.Fixtures = loaded
+		fmt.Printf("Loaded %d fixture outlets and %d products from %s\n", len(loaded.Outlets), len(loaded.Products), *fixturesDir)
 	}
 
-	numberOfOutletsString := r.Header.Get("X-Outlet-Num")
-	if numberOfOutletsString == "" {
-		numberOfOutletsString = "100"
+	if *restorePath != "" && !*statefulMode {
+		log.Fatal("-restore requires -stateful")
 	}
-	numberOfOutlets, err := strconv.Atoi(numberOfOutletsString)
-	if err != nil {
-		http.Error(w, "Invalid X-Outlet-Num header", http.StatusBadRequest)
-		return
+	if *restorePath != "" {
+		opts.Universe = store.New()
+		if err := opts.Universe.Load(*restorePath); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Restored %d outlets from %s\n", opts.Universe.Len(), *restorePath)
 	}
 
-	outlets := &pb.OutletDetailsResponse{
-		Details: []*pb.OutletDetails{},
+	if *proxyURL != "" {
+		upstream, err := proxy.New(*proxyURL, *recordDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.Upstream = upstream
+		fmt.Printf("Proxying to %s\n", *proxyURL)
 	}
 
-	outletChan := make(chan *pb.OutletDetails, numberOfOutlets)
-	errChan := make(chan error, numberOfOutlets)
-
-	for _ = range numberOfOutlets {
-		go func() {
-			outlet := mock.GenerateMockedOutlet(outletID, settings)
-			outletChan <- outlet
-			errChan <- nil // Signal successful generation
-		}()
+	mockServer := mockserver.New(opts)
+	if *statefulMode && *restorePath == "" {
+		fmt.Printf("Materialized %d outlets\n", mockServer.Outlets().Len())
 	}
 
-	for i := 0; i < numberOfOutlets; i++ {
-		outlet := <-outletChan
-		err := <-errChan
-		if err != nil {
-			http.Error(w, "Error generating outlet data", http.StatusInternalServerError)
-			return
+	for _, load := range []struct {
+		path string
+		load func(string) error
+	}{
+		{*faultsFile, mockServer.LoadFaults},
+		{*latencyFile, mockServer.LoadLatency},
+		{*scenarioFile, mockServer.LoadScenarios},
+		{*rateLimits, mockServer.LoadRateLimits},
+		{*mappingsDir, mockServer.LoadStubs},
+		{*journalFile, mockServer.AppendJournalTo},
+	} {
+		if load.path == "" {
+			continue
 		}
-		outlets.Details = append(outlets.Details, outlet)
+		if err := load.load(load.path); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *mappingsDir != "" {
+		fmt.Printf("Loaded %d stub mappings from %s\n", len(mockServer.Stubs()), *mappingsDir)
 	}
+	defer mockServer.Close()
 
-	var data []byte
+	server := &http.Server{Addr: ":" + PORT, Handler: mockServer}
+	server.RegisterOnShutdown(mockServer.EndStalls)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
 
-	if r.Header.Get("Accept") == "application/protobuf" {
-		data, err = proto.Marshal(outlets)
-		w.Header().Set("Content-Type", "application/protobuf")
-	} else {
-		data, err = protojson.Marshal(outlets)
-		w.Header().Set("Content-Type", "application/json")
-	}
-	if err != nil {
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
-		return
+	var grpcServer *grpc.Server
+	if *grpcPort != "" {
+		listener, err := net.Listen("tcp", ":"+*grpcPort)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grpcServer = grpc.NewServer()
+		mockServer.RegisterGRPC(grpcServer)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal(err)
+			}
+		}()
+		fmt.Printf("gRPC server starting on port %s\n", *grpcPort)
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write(data)
-}
+	fmt.Printf("Server starting on port %s\n", PORT)
+	fmt.Printf("Secret key required: %s\n", SECRET_KEY)
 
-func validateSecretKey(r *http.Request) bool {
-	authHeader := r.Header.Get("Authorization")
-	apiKey := r.Header.Get("X-API-Key")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
 
-	// Check both Authorization header and X-API-Key header
-	return authHeader == "Bearer "+SECRET_KEY || apiKey == SECRET_KEY
-}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Shutdown error: %v", err)
+	}
+	if grpcServer != nil {
+		// Long streams get the same grace period as HTTP requests
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}
 
-func handleDelay(r *http.Request) {
-	delayHeader := r.Header.Get("X-Delay-Ms")
-	if delayHeader != "" {
-		if delayMs, err := strconv.Atoi(delayHeader); err == nil && delayMs > 0 {
-			time.Sleep(time.Duration(delayMs) * time.Millisecond)
+	if *statefulMode && *snapshotPath != "" {
+		if outlets, err := mockServer.Snapshot(*snapshotPath); err != nil {
+			log.Printf("Failed to save snapshot: %v", err)
+		} else {
+			fmt.Printf("Saved %d outlets to %s\n", outlets, *snapshotPath)
 		}
 	}
 }
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Stats reports the cache usage for the admin API
+type Stats struct {
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+	Evictions  uint64 `json:"evictions"`
+	Entries    int    `json:"entries"`
+	Bytes      int64  `json:"bytes"`
+	MaxEntries int    `json:"maxEntries"`
+	MaxBytes   int64  `json:"maxBytes"`
+}
+
+// Entry is an encoded response with its cache validators
+type Entry struct {
+	Data         []byte
+	ETag         string
+	LastModified time.Time
+}
+
+type item struct {
+	key   string
+	entry Entry
+}
+
+// Cache is an LRU cache of encoded responses, bounded by entry count and total size
+type Cache struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	order      *list.List // most recently used first
+	items      map[string]*list.Element
+	bytes      int64
+
+	hits, misses, evictions uint64
+}
+
+// New creates a cache holding at most maxEntries responses of maxBytes in
+// total, 0 leaves the respective limit off
+func New(maxEntries int, maxBytes int64) *Cache {
+	return &Cache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		order:      list.New(),
+		items:      map[string]*list.Element{},
+	}
+}
+
+// Get returns the response stored under key, counting a hit or a miss
+func (c *Cache) Get(key string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return Entry{}, false
+	}
+	c.hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*item).entry, true
+}
+
+// Put stores a response, evicting the least recently used ones to stay within
+// the limits. Responses larger than the whole cache are not stored.
+func (c *Cache) Put(key string, entry Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.maxBytes > 0 && int64(len(entry.Data)) > c.maxBytes {
+		return
+	}
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+	c.items[key] = c.order.PushFront(&item{key: key, entry: entry})
+	c.bytes += int64(len(entry.Data))
+
+	for (c.maxEntries > 0 && c.order.Len() > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+func (c *Cache) remove(element *list.Element) {
+	removed := c.order.Remove(element).(*item)
+	delete(c.items, removed.key)
+	c.bytes -= int64(len(removed.entry.Data))
+}
+
+// Flush drops every response, the hit and miss counters keep running
+func (c *Cache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.items = map[string]*list.Element{}
+	c.bytes = 0
+}
+
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+		Entries:    c.order.Len(),
+		Bytes:      c.bytes,
+		MaxEntries: c.maxEntries,
+		MaxBytes:   c.maxBytes,
+	}
+}
@@ -0,0 +1,124 @@
+package conflict
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Resource types a rule can target
+const (
+	TypeOutlet        = "outlet"
+	TypeNote          = "note"
+	TypeChecklistItem = "checklistItem"
+)
+
+// Rule makes writes to matching records fail as if another client had just
+// changed them, to exercise conflict resolution
+type Rule struct {
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type"`               // TypeOutlet, TypeNote or TypeChecklistItem
+	OutletID string `json:"outletId,omitempty"` // empty matches every outlet
+	RecordID string `json:"recordId,omitempty"` // empty matches every record of the outlet, unused for outlets
+	Status   int    `json:"status,omitempty"`   // 409 (default) or 412
+	Times    int    `json:"times,omitempty"`    // writes the rule fails before it is removed, 0 fails every write
+}
+
+func (r Rule) Validate() error {
+	switch r.Type {
+	case TypeOutlet, TypeNote, TypeChecklistItem:
+	default:
+		return fmt.Errorf("type must be %q, %q or %q, got %q", TypeOutlet, TypeNote, TypeChecklistItem, r.Type)
+	}
+	if r.Status != 0 && r.Status != http.StatusConflict && r.Status != http.StatusPreconditionFailed {
+		return fmt.Errorf("status must be 409 or 412, got %d", r.Status)
+	}
+	if r.Times < 0 {
+		return fmt.Errorf("times must not be negative")
+	}
+	return nil
+}
+
+func (r Rule) matches(recordType, outletID, recordID string) bool {
+	return r.Type == recordType &&
+		(r.OutletID == "" || r.OutletID == outletID) &&
+		(r.RecordID == "" || r.Type == TypeOutlet || r.RecordID == recordID)
+}
+
+// Registry holds the forced conflict rules configured through the admin API
+type Registry struct {
+	mu     sync.Mutex
+	rules  []Rule
+	nextID int
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add validates and stores a rule, assigning an ID when it has none
+func (c *Registry) Add(rule Rule) (Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return rule, err
+	}
+	if rule.Status == 0 {
+		rule.Status = http.StatusConflict
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	if rule.ID == "" {
+		rule.ID = fmt.Sprintf("conflict-%d", c.nextID)
+	}
+	c.rules = append(c.rules, rule)
+	return rule, nil
+}
+
+// Remove deletes the rule with the given ID, reporting whether it existed
+func (c *Registry) Remove(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, rule := range c.rules {
+		if rule.ID == id {
+			c.rules = append(c.rules[:i], c.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Registry) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = nil
+}
+
+func (c *Registry) Rules() []Rule {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Rule{}, c.rules...)
+}
+
+// Match returns the first rule matching a write, counting it down and
+// removing it once it has failed its number of writes
+func (c *Registry) Match(recordType, outletID, recordID string) (Rule, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, rule := range c.rules {
+		if !rule.matches(recordType, outletID, recordID) {
+			continue
+		}
+		if rule.Times > 0 {
+			c.rules[i].Times--
+			if c.rules[i].Times == 0 {
+				c.rules = append(c.rules[:i], c.rules[i+1:]...)
+			}
+		}
+		return rule, true
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,282 @@
+package fault
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Rule makes a route fail with Status, or with a broken transport when Mode
+// is set, for a share of its requests
+type Rule struct {
+	ID          string  `json:"id,omitempty"`
+	Route       string  `json:"route,omitempty"` // path prefix, empty matches every route
+	Status      int     `json:"status,omitempty"`
+	Mode        string  `json:"mode,omitempty"`        // network fault mode, see ModeReset and friends
+	Bytes       int     `json:"bytes,omitempty"`       // meaning depends on Mode
+	StallMs     int     `json:"stallMs,omitempty"`     // ModeStall only: how long to hold the response, 0 until the client or server gives up
+	Probability float64 `json:"probability,omitempty"` // 0-1, 0 is treated as always
+	RetryAfter  int     `json:"retryAfterSeconds,omitempty"`
+	Message     string  `json:"message,omitempty"`
+}
+
+func (r Rule) Validate() error {
+	if r.Mode != "" {
+		if !validMode(r.Mode) {
+			return fmt.Errorf("unknown mode %q", r.Mode)
+		}
+		if r.Bytes < 0 && r.Mode != ModeWrongLength {
+			return fmt.Errorf("bytes must not be negative for mode %q", r.Mode)
+		}
+	} else if r.Status < 400 || r.Status > 599 {
+		return fmt.Errorf("status must be 4xx or 5xx, got %d", r.Status)
+	}
+	if r.Probability < 0 || r.Probability > 1 {
+		return fmt.Errorf("probability must be between 0 and 1, got %g", r.Probability)
+	}
+	if r.RetryAfter < 0 {
+		return fmt.Errorf("retryAfterSeconds must not be negative")
+	}
+	if r.StallMs < 0 {
+		return fmt.Errorf("stallMs must not be negative")
+	}
+	if r.StallMs > 0 && r.Mode != ModeStall {
+		return fmt.Errorf("stallMs needs mode %q", ModeStall)
+	}
+	return nil
+}
+
+func (r Rule) matches(path string) bool {
+	return r.Route == "" || r.Route == "*" || strings.HasPrefix(path, r.Route)
+}
+
+// Fires rolls the dice on the rule's probability
+func (r Rule) Fires() bool {
+	return r.Probability == 0 || r.Probability >= 1 || rand.Float64() < r.Probability
+}
+
+// Injector holds the fault rules configured through the config file and admin API
+type Injector struct {
+	mu     sync.RWMutex
+	rules  []Rule
+	nextID int
+}
+
+func NewInjector() *Injector {
+	return &Injector{}
+}
+
+// Add validates and stores a rule, assigning an ID when it has none
+func (i *Injector) Add(rule Rule) (Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return rule, err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.nextID++
+	if rule.ID == "" {
+		rule.ID = fmt.Sprintf("fault-%d", i.nextID)
+	}
+	i.rules = append(i.rules, rule)
+	return rule, nil
+}
+
+// Remove deletes the rule with the given ID, reporting whether it existed
+func (i *Injector) Remove(id string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for idx, rule := range i.rules {
+		if rule.ID == id {
+			i.rules = append(i.rules[:idx], i.rules[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Injector) Clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = nil
+}
+
+func (i *Injector) Rules() []Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return append([]Rule{}, i.rules...)
+}
+
+// LoadFile adds the rules from a JSON file containing an array of rules
+func (i *Injector) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, rule := range rules {
+		if _, err := i.Add(rule); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Pick returns the fault to inject for a request, if any. A fault requested
+// through the X-Fault-* headers takes precedence over the configured rules;
+// otherwise the first matching rule that fires wins.
+func (i *Injector) Pick(r *http.Request) (Rule, bool, error) {
+	rule, ok, err := FromHeaders(r)
+	if err != nil {
+		return rule, false, err
+	}
+	if ok {
+		return rule, rule.Fires(), nil
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, rule := range i.rules {
+		if rule.matches(r.URL.Path) && rule.Fires() {
+			return rule, true, nil
+		}
+	}
+	return Rule{}, false, nil
+}
+
+// FromHeaders reads a one-off fault from the request headers:
+//   - X-Fault-Status: status code to return
+//   - X-Fault-Mode: network fault mode instead of a status
+//   - X-Fault-Bytes: byte count for the network fault mode
+//   - X-Fault-Stall-Ms: how long the stall mode holds the response
+//   - X-Fault-Probability: chance between 0 and 1 (default 1)
+//   - X-Fault-Retry-After: Retry-After in seconds
+func FromHeaders(r *http.Request) (Rule, bool, error) {
+	statusHeader := r.Header.Get("X-Fault-Status")
+	modeHeader := r.Header.Get("X-Fault-Mode")
+	if statusHeader == "" && modeHeader == "" {
+		return Rule{}, false, nil
+	}
+
+	rule := Rule{ID: "header", Route: r.URL.Path, Mode: modeHeader}
+	var err error
+	if statusHeader != "" {
+		if rule.Status, err = strconv.Atoi(statusHeader); err != nil {
+			return rule, false, fmt.Errorf("Invalid X-Fault-Status header")
+		}
+	}
+	if value := r.Header.Get("X-Fault-Bytes"); value != "" {
+		if rule.Bytes, err = strconv.Atoi(value); err != nil {
+			return rule, false, fmt.Errorf("Invalid X-Fault-Bytes header")
+		}
+	}
+	if value := r.Header.Get("X-Fault-Stall-Ms"); value != "" {
+		if rule.StallMs, err = strconv.Atoi(value); err != nil {
+			return rule, false, fmt.Errorf("Invalid X-Fault-Stall-Ms header")
+		}
+	}
+	if value := r.Header.Get("X-Fault-Probability"); value != "" {
+		if rule.Probability, err = strconv.ParseFloat(value, 64); err != nil {
+			return rule, false, fmt.Errorf("Invalid X-Fault-Probability header")
+		}
+	}
+	if value := r.Header.Get("X-Fault-Retry-After"); value != "" {
+		if rule.RetryAfter, err = strconv.Atoi(value); err != nil {
+			return rule, false, fmt.Errorf("Invalid X-Fault-Retry-After header")
+		}
+	}
+	if err := rule.Validate(); err != nil {
+		return rule, false, fmt.Errorf("Invalid fault headers: %w", err)
+	}
+	return rule, true, nil
+}
+
+// WriteError writes the error response a real deployment would produce for the
+// rule's status: gateway errors come from the proxy as HTML, everything else
+// from the backend as JSON.
+func WriteError(w http.ResponseWriter, rule Rule) {
+	retryAfter := rule.RetryAfter
+	if retryAfter == 0 && (rule.Status == http.StatusTooManyRequests || rule.Status == http.StatusServiceUnavailable) {
+		retryAfter = rand.Intn(10) + 1
+	}
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+
+	message := rule.Message
+	if message == "" {
+		message = defaultMessage(rule.Status)
+	}
+
+	if rule.Status == http.StatusBadGateway || rule.Status == http.StatusGatewayTimeout {
+		title := fmt.Sprintf("%d %s", rule.Status, http.StatusText(rule.Status))
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Server", "nginx")
+		w.WriteHeader(rule.Status)
+		fmt.Fprintf(w, "<html>\r\n<head><title>%s</title></head>\r\n<body>\r\n<center><h1>%s</h1></center>\r\n<hr><center>nginx</center>\r\n</body>\r\n</html>\r\n", title, title)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(rule.Status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    rule.Status,
+			"status":  errorStatus(rule.Status),
+			"message": message,
+		},
+	})
+}
+
+func defaultMessage(status int) string {
+	switch status {
+	case http.StatusTooManyRequests:
+		return "Rate limit exceeded, please retry later"
+	case http.StatusInternalServerError:
+		return "Internal error encountered"
+	case http.StatusServiceUnavailable:
+		return "The service is currently unavailable"
+	default:
+		return http.StatusText(status)
+	}
+}
+
+// errorStatus maps HTTP codes to the canonical error names used by Google-style APIs
+func errorStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "INVALID_ARGUMENT"
+	case http.StatusUnauthorized:
+		return "UNAUTHENTICATED"
+	case http.StatusForbidden:
+		return "PERMISSION_DENIED"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusConflict:
+		return "ABORTED"
+	case http.StatusTooManyRequests:
+		return "RESOURCE_EXHAUSTED"
+	case http.StatusNotImplemented:
+		return "UNIMPLEMENTED"
+	case http.StatusServiceUnavailable:
+		return "UNAVAILABLE"
+	case http.StatusGatewayTimeout:
+		return "DEADLINE_EXCEEDED"
+	default:
+		if status >= 500 {
+			return "INTERNAL"
+		}
+		return "FAILED_PRECONDITION"
+	}
+}
@@ -0,0 +1,157 @@
+package fault
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Network fault modes break the transport instead of returning an error status.
+// Rule.Bytes tunes them as noted per mode.
+const (
+	ModeReset       = "reset"        // send Bytes of the body, then reset the connection (default half)
+	ModeTruncate    = "truncate"     // send Bytes of the body, then close the connection (default half)
+	ModeWrongLength = "wrong-length" // declare a Content-Length off by Bytes, may be negative (default +100)
+	ModeStall       = "stall"        // send the headers and never the body, then close the connection after StallMs
+	ModeCorrupt     = "corrupt"      // flip Bytes random bytes of the body (default 1%)
+)
+
+func validMode(mode string) bool {
+	switch mode {
+	case ModeReset, ModeTruncate, ModeWrongLength, ModeStall, ModeCorrupt:
+		return true
+	}
+	return false
+}
+
+// WriteBody sends status and data with the transport failure described by
+// rule.Mode. Content-Type must already be set. Modes that drop the connection
+// abort the handler the way net/http expects, so nothing may be written afterwards.
+func WriteBody(w http.ResponseWriter, r *http.Request, rule Rule, status int, data []byte) {
+	switch rule.Mode {
+	case ModeReset, ModeTruncate:
+		cut := min(rule.Bytes, len(data))
+		if rule.Bytes == 0 {
+			cut = len(data) / 2
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		w.Write(data[:cut])
+		if rule.Mode == ModeReset && resetConnection(w) {
+			return
+		}
+		abort(w)
+
+	case ModeWrongLength:
+		offset := rule.Bytes
+		if offset == 0 {
+			offset = 100
+		}
+		writeRaw(w, status, max(len(data)+offset, 0), data)
+
+	case ModeStall:
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		http.NewResponseController(w).Flush()
+		var timeout <-chan time.Time
+		if rule.StallMs > 0 {
+			timer := time.NewTimer(time.Duration(rule.StallMs) * time.Millisecond)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case <-r.Context().Done():
+		case <-timeout:
+		}
+		abort(w)
+
+	case ModeCorrupt:
+		corrupted := corrupt(data, rule.Bytes)
+		w.Header().Set("Content-Length", strconv.Itoa(len(corrupted)))
+		w.WriteHeader(status)
+		w.Write(corrupted)
+	}
+}
+
+// abort flushes what was written so far and lets net/http close the connection
+func abort(w http.ResponseWriter) {
+	http.NewResponseController(w).Flush()
+	panic(http.ErrAbortHandler)
+}
+
+// resetConnection closes the connection with a TCP RST instead of a FIN.
+// It reports false when the connection can't be hijacked (HTTP/2).
+func resetConnection(w http.ResponseWriter) bool {
+	controller := http.NewResponseController(w)
+	controller.Flush()
+	conn, _, err := controller.Hijack()
+	if err != nil {
+		return false
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		tcpConn.SetLinger(0)
+	}
+	conn.Close()
+	return true
+}
+
+// StatusRecorder is implemented by writers that record the status of a
+// response, e.g. for a request log. Responses written straight to the
+// connection bypass WriteHeader and report their status this way instead.
+type StatusRecorder interface {
+	RecordStatus(status int)
+}
+
+// writeRaw writes the response straight to the connection, bypassing the
+// Content-Length checks of net/http, and closes it afterwards
+func writeRaw(w http.ResponseWriter, status, contentLength int, data []byte) {
+	conn, buf, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		abort(w)
+		return
+	}
+	defer conn.Close()
+	recordStatus(w, status)
+
+	header := w.Header().Clone()
+	header.Set("Content-Length", strconv.Itoa(contentLength))
+	header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+
+	fmt.Fprintf(buf, "HTTP/1.1 %d %s\r\n", status, http.StatusText(status))
+	header.Write(buf)
+	buf.WriteString("\r\n")
+	buf.Write(data)
+	buf.Flush()
+}
+
+// recordStatus reports status to every StatusRecorder among w and the writers it wraps
+func recordStatus(w http.ResponseWriter, status int) {
+	for {
+		if recorder, ok := w.(StatusRecorder); ok {
+			recorder.RecordStatus(status)
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = unwrapper.Unwrap()
+	}
+}
+
+// corrupt returns a copy of data with n bytes flipped at random positions
+func corrupt(data []byte, n int) []byte {
+	corrupted := append([]byte{}, data...)
+	if len(corrupted) == 0 {
+		return corrupted
+	}
+	if n <= 0 {
+		n = max(len(corrupted)/100, 1)
+	}
+	for range n {
+		corrupted[rand.Intn(len(corrupted))] ^= byte(rand.Intn(255) + 1)
+	}
+	return corrupted
+}
@@ -0,0 +1,145 @@
+package fieldmask
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Mask is a parsed google.protobuf.FieldMask, the tree of fields a response
+// keeps. Paths into repeated messages apply to every element. A nil Mask keeps
+// every field.
+type Mask struct {
+	fields map[protoreflect.Name]*Mask // a nil child keeps the whole field
+}
+
+// Parse resolves paths like "statistics.top_products" against a message type.
+// Fields may be named like in the .proto file or in lowerCamelCase like in JSON.
+// No paths give a nil Mask.
+func Parse(desc protoreflect.MessageDescriptor, paths []string) (*Mask, error) {
+	var root *Mask
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if root == nil {
+			root = &Mask{fields: map[protoreflect.Name]*Mask{}}
+		}
+		if err := root.add(desc, path); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+func (m *Mask) add(desc protoreflect.MessageDescriptor, path string) error {
+	parts := strings.Split(path, ".")
+	node := m
+	for i, part := range parts {
+		if desc == nil {
+			return fmt.Errorf("field %q of path %q has no subfields", parts[i-1], path)
+		}
+		fd := desc.Fields().ByName(protoreflect.Name(part))
+		if fd == nil {
+			fd = desc.Fields().ByJSONName(part)
+		}
+		if fd == nil {
+			return fmt.Errorf("unknown field %q in path %q", part, path)
+		}
+
+		child, exists := node.fields[fd.Name()]
+		if exists && child == nil {
+			// An enclosing path already keeps the whole field
+			return nil
+		}
+		if i == len(parts)-1 {
+			node.fields[fd.Name()] = nil
+			return nil
+		}
+		if !exists {
+			child = &Mask{fields: map[protoreflect.Name]*Mask{}}
+			node.fields[fd.Name()] = child
+		}
+		node = child
+		desc = nil
+		if fd.Message() != nil && !fd.IsMap() {
+			desc = fd.Message()
+		}
+	}
+	return nil
+}
+
+// Prune clears the fields of msg the mask does not keep
+func (m *Mask) Prune(msg proto.Message) {
+	if m == nil {
+		return
+	}
+	m.prune(msg.ProtoReflect())
+}
+
+func (m *Mask) prune(msg protoreflect.Message) {
+	var cleared []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		child, ok := m.fields[fd.Name()]
+		switch {
+		case !ok:
+			cleared = append(cleared, fd)
+		case child == nil:
+		case fd.IsList():
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				child.prune(list.Get(i).Message())
+			}
+		default:
+			child.prune(value.Message())
+		}
+		return true
+	})
+	for _, fd := range cleared {
+		msg.Clear(fd)
+	}
+}
+
+// Includes reports whether the mask keeps any part of the field at a dotted
+// path of .proto names
+func (m *Mask) Includes(path string) bool {
+	node := m
+	for _, part := range strings.Split(path, ".") {
+		if node == nil {
+			return true
+		}
+		child, ok := node.fields[protoreflect.Name(part)]
+		if !ok {
+			return false
+		}
+		node = child
+	}
+	return true
+}
+
+// String lists the kept paths sorted and comma separated, the same for
+// equivalent masks
+func (m *Mask) String() string {
+	if m == nil {
+		return ""
+	}
+	return strings.Join(m.paths(""), ",")
+}
+
+func (m *Mask) paths(prefix string) []string {
+	var paths []string
+	for name, child := range m.fields {
+		path := prefix + string(name)
+		if child == nil {
+			paths = append(paths, path)
+		} else {
+			paths = append(paths, child.paths(path+".")...)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
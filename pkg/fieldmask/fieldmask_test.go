@@ -0,0 +1,115 @@
+package fieldmask
+
+import (
+	"testing"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParse(t *testing.T) {
+	desc := (&pb.OutletDetails{}).ProtoReflect().Descriptor()
+	tests := []struct {
+		name    string
+		paths   []string
+		want    string
+		wantErr bool
+	}{
+		{name: "no paths", paths: nil, want: ""},
+		{name: "blank paths", paths: []string{" ", ""}, want: ""},
+		{name: "proto names", paths: []string{"name", "statistics.top_products"}, want: "name,statistics.top_products"},
+		{name: "json names", paths: []string{"outletId", "statistics.topProducts"}, want: "outlet_id,statistics.top_products"},
+		{name: "enclosing path wins", paths: []string{"statistics", "statistics.top_products"}, want: "statistics"},
+		{name: "enclosing path added later wins", paths: []string{"statistics.top_products", "statistics"}, want: "statistics"},
+		{name: "repeated message", paths: []string{"visit_history.visit_id"}, want: "visit_history.visit_id"},
+		{name: "unknown field", paths: []string{"nope"}, wantErr: true},
+		{name: "unknown subfield", paths: []string{"location.nope"}, wantErr: true},
+		{name: "subfield of scalar", paths: []string{"name.first"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := Parse(desc, tt.paths)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %q, want an error", tt.paths, mask)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.paths, err)
+			}
+			if got := mask.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.paths, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	outlet := func() *pb.OutletDetails {
+		return &pb.OutletDetails{
+			OutletId: "outlet-001",
+			Name:     "Corner Shop",
+			Location: &pb.Location{City: "Utrecht", Country: "Nederland"},
+			VisitHistory: []*pb.Visit{
+				{VisitId: "visit-001", Summary: "Restocked"},
+				{VisitId: "visit-002", Summary: "Talked prices"},
+			},
+		}
+	}
+	tests := []struct {
+		name  string
+		paths []string
+		want  *pb.OutletDetails
+	}{
+		{name: "no mask keeps everything", paths: nil, want: outlet()},
+		{name: "top level fields", paths: []string{"outlet_id", "name"}, want: &pb.OutletDetails{OutletId: "outlet-001", Name: "Corner Shop"}},
+		{name: "nested field", paths: []string{"location.city"}, want: &pb.OutletDetails{Location: &pb.Location{City: "Utrecht"}}},
+		{
+			name:  "every element of a repeated message",
+			paths: []string{"visit_history.visit_id"},
+			want: &pb.OutletDetails{VisitHistory: []*pb.Visit{
+				{VisitId: "visit-001"},
+				{VisitId: "visit-002"},
+			}},
+		},
+	}
+	desc := (&pb.OutletDetails{}).ProtoReflect().Descriptor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := Parse(desc, tt.paths)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := outlet()
+			mask.Prune(got)
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("Prune(%q) = %v, want %v", tt.paths, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	mask, err := Parse((&pb.OutletDetails{}).ProtoReflect().Descriptor(), []string{"statistics.top_products", "name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"name", true},
+		{"statistics", true},
+		{"statistics.top_products", true},
+		{"statistics.top_products.product_id", true},
+		{"statistics.total_orders", false},
+		{"location", false},
+	}
+	for _, tt := range tests {
+		if got := mask.Includes(tt.path); got != tt.want {
+			t.Errorf("Includes(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package fixtures
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// csvTable is one entity written by WriteCSV, rows returns its rows for an outlet
+type csvTable struct {
+	file    string
+	columns []string
+	rows    func(outlet *pb.OutletDetails) [][]string
+}
+
+// csvTables share the column names of Load, so outlets.csv and contacts.csv
+// can be read back as fixtures
+var csvTables = []csvTable{
+	{
+		file:    "outlets.csv",
+		columns: []string{"outlet_id", "name", "code", "type", "status", "address", "city", "state", "postal_code", "country", "latitude", "longitude", "created_at", "updated_at"},
+		rows: func(o *pb.OutletDetails) [][]string {
+			location := o.Location
+			if location == nil {
+				location = &pb.Location{}
+			}
+			return [][]string{{o.OutletId, o.Name, o.Code, o.Type.String(), o.Status.String(),
+				location.Address, location.City, location.State, location.PostalCode, location.Country,
+				formatFloat(location.Latitude), formatFloat(location.Longitude), formatTime(o.CreatedAt), formatTime(o.UpdatedAt)}}
+		},
+	},
+	{
+		file:    "contacts.csv",
+		columns: []string{"outlet_id", "contact_id", "name", "role", "phone", "email", "type", "is_primary", "created_at"},
+		rows: func(o *pb.OutletDetails) (rows [][]string) {
+			for _, c := range o.ContactPoints {
+				rows = append(rows, []string{o.OutletId, c.ContactId, c.Name, c.Role, c.Phone, c.Email, c.Type.String(), strconv.FormatBool(c.IsPrimary), formatTime(c.CreatedAt)})
+			}
+			return rows
+		},
+	},
+	{
+		file:    "visits.csv",
+		columns: []string{"outlet_id", "visit_id", "sales_rep_id", "sales_rep_name", "visit_date", "visit_type", "visit_status", "purpose", "summary", "duration_seconds"},
+		rows: func(o *pb.OutletDetails) (rows [][]string) {
+			for _, v := range o.VisitHistory {
+				rows = append(rows, []string{o.OutletId, v.VisitId, v.SalesRepId, v.SalesRepName, formatTime(v.VisitDate), v.VisitType.String(), v.VisitStatus.String(), v.Purpose, v.Summary, strconv.Itoa(int(v.DurationSeconds))})
+			}
+			return rows
+		},
+	},
+	{
+		file:    "orders.csv",
+		columns: []string{"outlet_id", "order_id", "order_number", "order_date", "status", "subtotal_amount", "vat_amount", "total_amount", "currency", "payment_method", "payment_status", "sales_rep_id", "sales_rep_name", "delivery_date"},
+		rows: func(o *pb.OutletDetails) (rows [][]string) {
+			for _, order := range o.OrderHistory {
+				payment := order.PaymentInfo
+				if payment == nil {
+					payment = &pb.PaymentInfo{}
+				}
+				rows = append(rows, []string{o.OutletId, order.OrderId, order.OrderNumber, formatTime(order.OrderDate), order.Status.String(),
+					formatFloat(order.SubtotalAmount), formatFloat(order.VatAmount), formatFloat(order.TotalAmount), order.Currency,
+					payment.Method.String(), payment.Status.String(), order.SalesRepId, order.SalesRepName, formatTime(order.DeliveryDate)})
+			}
+			return rows
+		},
+	},
+	{
+		file:    "order_items.csv",
+		columns: []string{"outlet_id", "order_id", "product_id", "product_name", "sku", "quantity", "unit_price", "discount_percentage", "discount_amount", "total_price", "vat_rate", "vat_amount"},
+		rows: func(o *pb.OutletDetails) (rows [][]string) {
+			for _, order := range o.OrderHistory {
+				for _, item := range order.Items {
+					rows = append(rows, []string{o.OutletId, order.OrderId, item.ProductId, item.ProductName, item.Sku, strconv.Itoa(int(item.Quantity)),
+						formatFloat(item.UnitPrice), formatFloat(item.DiscountPercentage), formatFloat(item.DiscountAmount), formatFloat(item.TotalPrice),
+						formatFloat(item.VatRate), formatFloat(item.VatAmount)})
+				}
+			}
+			return rows
+		},
+	},
+	{
+		file:    "statistics.csv",
+		columns: []string{"outlet_id", "currency", "total_revenue_ytd", "total_revenue_last_year", "average_order_value", "total_orders_ytd", "total_visits_ytd", "segment", "credit_limit", "credit_used"},
+		rows: func(o *pb.OutletDetails) [][]string {
+			stats := o.Statistics
+			if stats == nil {
+				return nil
+			}
+			credit := stats.CreditInfo
+			if credit == nil {
+				credit = &pb.CreditInfo{}
+			}
+			return [][]string{{o.OutletId, stats.Currency, formatFloat(stats.TotalRevenueYtd), formatFloat(stats.TotalRevenueLastYear), formatFloat(stats.AverageOrderValue),
+				strconv.Itoa(int(stats.TotalOrdersYtd)), strconv.Itoa(int(stats.TotalVisitsYtd)), stats.Segment.String(), formatFloat(credit.CreditLimit), formatFloat(credit.CreditUsed)}}
+		},
+	},
+	{
+		file:    "nearby_outlets.csv",
+		columns: []string{"outlet_id", "nearby_outlet_id", "name", "type", "distance_km", "is_competitor", "relationship"},
+		rows: func(o *pb.OutletDetails) (rows [][]string) {
+			for _, n := range o.OutletsNearby {
+				rows = append(rows, []string{o.OutletId, n.OutletId, n.Name, n.Type.String(), formatFloat(n.DistanceKm), strconv.FormatBool(n.IsCompetitor), n.Relationship})
+			}
+			return rows
+		},
+	},
+	{
+		file:    "notes.csv",
+		columns: []string{"outlet_id", "note_id", "title", "content", "type", "created_by", "created_at", "is_private"},
+		rows: func(o *pb.OutletDetails) (rows [][]string) {
+			for _, n := range o.Notes {
+				rows = append(rows, []string{o.OutletId, n.NoteId, n.Title, n.Content, n.Type.String(), n.CreatedBy, formatTime(n.CreatedAt), strconv.FormatBool(n.IsPrivate)})
+			}
+			return rows
+		},
+	},
+	{
+		file:    "assets.csv",
+		columns: []string{"outlet_id", "asset_id", "name", "type", "model", "serial_number", "status", "installation_date", "condition"},
+		rows: func(o *pb.OutletDetails) (rows [][]string) {
+			for _, a := range o.AssetList {
+				rows = append(rows, []string{o.OutletId, a.AssetId, a.Name, a.Type.String(), a.Model, a.SerialNumber, a.Status.String(), formatTime(a.InstallationDate), a.Condition})
+			}
+			return rows
+		},
+	},
+	{
+		file:    "checklist.csv",
+		columns: []string{"outlet_id", "item_id", "title", "category", "status", "priority", "due_date", "completed_date", "assigned_to"},
+		rows: func(o *pb.OutletDetails) (rows [][]string) {
+			for _, c := range o.Checklist {
+				rows = append(rows, []string{o.OutletId, c.ItemId, c.Title, c.Category.String(), c.Status.String(), c.Priority.String(), formatTime(c.DueDate), formatTime(c.CompletedDate), c.AssignedTo})
+			}
+			return rows
+		},
+	},
+	{
+		file:    "news.csv",
+		columns: []string{"outlet_id", "news_id", "title", "type", "source", "published_date", "author", "is_important"},
+		rows: func(o *pb.OutletDetails) (rows [][]string) {
+			for _, n := range o.News {
+				rows = append(rows, []string{o.OutletId, n.NewsId, n.Title, n.Type.String(), n.Source.String(), formatTime(n.PublishedDate), n.Author, strconv.FormatBool(n.IsImportant)})
+			}
+			return rows
+		},
+	},
+}
+
+// WriteCSV writes one CSV file per entity to dir, every row keyed by outlet_id
+func WriteCSV(dir string, outlets []*pb.OutletDetails) error {
+	for _, table := range csvTables {
+		if err := writeCSVTable(filepath.Join(dir, table.file), table, outlets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSVTable(path string, table csvTable, outlets []*pb.OutletDetails) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(table.columns); err != nil {
+		return err
+	}
+	for _, outlet := range outlets {
+		for _, row := range table.rows(outlet) {
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatTime(timestamp *timestamppb.Timestamp) string {
+	if timestamp == nil {
+		return ""
+	}
+	return timestamp.AsTime().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,299 @@
+package fixtures
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/mock"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Fixtures are hand-written outlets and products mixed into the generated data.
+// Outlets only need the fields a scenario cares about, the rest is filled in
+// by mock.CompleteOutlet.
+type Fixtures struct {
+	Outlets  []*pb.OutletDetails
+	Products []mock.Product
+}
+
+// Load reads every fixture file in dir:
+//   - *.json: protojson of a single OutletDetails
+//   - outlets.csv: outlet_id,name,code,type,status,address,city,state,postal_code,country,latitude,longitude
+//   - contacts.csv: outlet_id,name,role,phone,email,type,is_primary
+//   - products.csv: product_id,name,sku,list_price
+//
+// CSV files need a header row, columns may appear in any order and all but
+// outlet_id (and name for products) are optional.
+func Load(dir string) (*Fixtures, error) {
+	fixtures := &Fixtures{}
+
+	jsonFiles, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(jsonFiles)
+	for _, path := range jsonFiles {
+		outlet, err := loadJSONOutlet(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures.Outlets = append(fixtures.Outlets, outlet)
+	}
+
+	if err := readCSV(filepath.Join(dir, "outlets.csv"), func(row csvRow) error {
+		outlet, err := outletFromRow(row)
+		if err != nil {
+			return err
+		}
+		fixtures.Outlets = append(fixtures.Outlets, outlet)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := readCSV(filepath.Join(dir, "contacts.csv"), func(row csvRow) error {
+		return fixtures.addContact(row)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := readCSV(filepath.Join(dir, "products.csv"), func(row csvRow) error {
+		product, err := productFromRow(row, len(fixtures.Products))
+		if err != nil {
+			return err
+		}
+		fixtures.Products = append(fixtures.Products, product)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, outlet := range fixtures.Outlets {
+		if outlet.OutletId == "" {
+			return nil, fmt.Errorf("fixture outlet %q has no outlet_id", outlet.Name)
+		}
+		if seen[outlet.OutletId] {
+			return nil, fmt.Errorf("duplicate fixture outlet %q", outlet.OutletId)
+		}
+		seen[outlet.OutletId] = true
+	}
+
+	return fixtures, nil
+}
+
+func loadJSONOutlet(path string) (*pb.OutletDetails, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	outlet := &pb.OutletDetails{}
+	if err := protojson.Unmarshal(data, outlet); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return outlet, nil
+}
+
+func (f *Fixtures) addContact(row csvRow) error {
+	outletID := row.get("outlet_id")
+	var outlet *pb.OutletDetails
+	for _, o := range f.Outlets {
+		if o.OutletId == outletID {
+			outlet = o
+			break
+		}
+	}
+	if outlet == nil {
+		return row.errorf("unknown outlet_id %q", outletID)
+	}
+
+	contactType := pb.ContactType_CONTACT_TYPE_MANAGER
+	if value := row.get("type"); value != "" {
+		parsed, ok := parseEnum(pb.ContactType_value, "CONTACT_TYPE_", value)
+		if !ok {
+			return row.errorf("invalid contact type %q", value)
+		}
+		contactType = pb.ContactType(parsed)
+	}
+
+	isPrimary := len(outlet.ContactPoints) == 0
+	if value := row.get("is_primary"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return row.errorf("invalid is_primary %q", value)
+		}
+		isPrimary = parsed
+	}
+
+	outlet.ContactPoints = append(outlet.ContactPoints, &pb.ContactPoint{
+		ContactId: fmt.Sprintf("contact-%03d", len(outlet.ContactPoints)+1),
+		Name:      row.get("name"),
+		Role:      row.get("role"),
+		Phone:     row.get("phone"),
+		Email:     row.get("email"),
+		Type:      contactType,
+		IsPrimary: isPrimary,
+		CreatedAt: timestamppb.Now(),
+	})
+	return nil
+}
+
+func outletFromRow(row csvRow) (*pb.OutletDetails, error) {
+	outlet := &pb.OutletDetails{
+		OutletId: row.get("outlet_id"),
+		Name:     row.get("name"),
+		Code:     row.get("code"),
+	}
+
+	if value := row.get("type"); value != "" {
+		parsed, ok := parseEnum(pb.OutletType_value, "OUTLET_TYPE_", value)
+		if !ok {
+			return nil, row.errorf("invalid outlet type %q", value)
+		}
+		outlet.Type = pb.OutletType(parsed)
+	}
+	if value := row.get("status"); value != "" {
+		parsed, ok := parseEnum(pb.OutletStatus_value, "OUTLET_STATUS_", value)
+		if !ok {
+			return nil, row.errorf("invalid outlet status %q", value)
+		}
+		outlet.Status = pb.OutletStatus(parsed)
+	}
+
+	location := &pb.Location{
+		Address:    row.get("address"),
+		City:       row.get("city"),
+		State:      row.get("state"),
+		PostalCode: row.get("postal_code"),
+		Country:    row.get("country"),
+	}
+	var err error
+	if location.Latitude, err = row.getFloat("latitude"); err != nil {
+		return nil, err
+	}
+	if location.Longitude, err = row.getFloat("longitude"); err != nil {
+		return nil, err
+	}
+	// Leave the location to the generator when no column was provided
+	if location.Address != "" || location.City != "" || location.State != "" || location.PostalCode != "" ||
+		location.Country != "" || location.Latitude != 0 || location.Longitude != 0 {
+		outlet.Location = location
+	}
+
+	return outlet, nil
+}
+
+func productFromRow(row csvRow, index int) (mock.Product, error) {
+	product := mock.Product{
+		ProductID: row.get("product_id"),
+		Name:      row.get("name"),
+		SKU:       row.get("sku"),
+	}
+	if product.Name == "" {
+		return product, row.errorf("product name is required")
+	}
+	// Without a list price the product is priced from each market's range
+	listPrice, err := row.getFloat("list_price")
+	if err != nil {
+		return product, err
+	}
+	product.ListPrice = listPrice
+	if product.ProductID == "" {
+		product.ProductID = fmt.Sprintf("prod-%03d", index+1)
+	}
+	if product.SKU == "" {
+		product.SKU = fmt.Sprintf("SKU-%03d", index+1)
+	}
+	return product, nil
+}
+
+// parseEnum accepts both the full enum name (OUTLET_TYPE_RETAIL) and the
+// short, case-insensitive form (retail)
+func parseEnum(values map[string]int32, prefix, value string) (int32, bool) {
+	name := strings.ToUpper(strings.TrimSpace(value))
+	if !strings.HasPrefix(name, prefix) {
+		name = prefix + strings.ReplaceAll(name, " ", "_")
+	}
+	parsed, ok := values[name]
+	return parsed, ok
+}
+
+type csvRow struct {
+	path    string
+	line    int
+	columns map[string]int
+	record  []string
+}
+
+func (r csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r csvRow) getFloat(column string) (float64, error) {
+	value := r.get(column)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, r.errorf("invalid %s %q", column, value)
+	}
+	return parsed, nil
+}
+
+func (r csvRow) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", r.path, r.line, fmt.Sprintf(format, args...))
+}
+
+// readCSV calls fn for every data row of path. A missing file is not an error.
+func readCSV(path string, fn func(row csvRow) error) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := fn(csvRow{path: path, line: line, columns: columns, record: record}); err != nil {
+			return err
+		}
+	}
+}
@@ -1939,22 +1939,25 @@ func (x *VisitAction) GetDueDate() *timestamppb.Timestamp {
 
 // Order history
 type Order struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	OrderNumber   string                 `protobuf:"bytes,2,opt,name=order_number,json=orderNumber,proto3" json:"order_number,omitempty"`
-	OrderDate     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=order_date,json=orderDate,proto3" json:"order_date,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,4,opt,name=status,proto3,enum=outlet.OrderStatus" json:"status,omitempty"`
-	TotalAmount   float64                `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
-	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
-	Items         []*OrderItem           `protobuf:"bytes,7,rep,name=items,proto3" json:"items,omitempty"`
-	PaymentInfo   *PaymentInfo           `protobuf:"bytes,8,opt,name=payment_info,json=paymentInfo,proto3" json:"payment_info,omitempty"`
-	DeliveryInfo  *DeliveryInfo          `protobuf:"bytes,9,opt,name=delivery_info,json=deliveryInfo,proto3" json:"delivery_info,omitempty"`
-	SalesRepId    string                 `protobuf:"bytes,10,opt,name=sales_rep_id,json=salesRepId,proto3" json:"sales_rep_id,omitempty"`
-	SalesRepName  string                 `protobuf:"bytes,11,opt,name=sales_rep_name,json=salesRepName,proto3" json:"sales_rep_name,omitempty"`
-	DeliveryDate  *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=delivery_date,json=deliveryDate,proto3" json:"delivery_date,omitempty"`
-	Notes         string                 `protobuf:"bytes,13,opt,name=notes,proto3" json:"notes,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	OrderId      string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	OrderNumber  string                 `protobuf:"bytes,2,opt,name=order_number,json=orderNumber,proto3" json:"order_number,omitempty"`
+	OrderDate    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=order_date,json=orderDate,proto3" json:"order_date,omitempty"`
+	Status       OrderStatus            `protobuf:"varint,4,opt,name=status,proto3,enum=outlet.OrderStatus" json:"status,omitempty"`
+	TotalAmount  float64                `protobuf:"fixed64,5,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	Currency     string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	Items        []*OrderItem           `protobuf:"bytes,7,rep,name=items,proto3" json:"items,omitempty"`
+	PaymentInfo  *PaymentInfo           `protobuf:"bytes,8,opt,name=payment_info,json=paymentInfo,proto3" json:"payment_info,omitempty"`
+	DeliveryInfo *DeliveryInfo          `protobuf:"bytes,9,opt,name=delivery_info,json=deliveryInfo,proto3" json:"delivery_info,omitempty"`
+	SalesRepId   string                 `protobuf:"bytes,10,opt,name=sales_rep_id,json=salesRepId,proto3" json:"sales_rep_id,omitempty"`
+	SalesRepName string                 `protobuf:"bytes,11,opt,name=sales_rep_name,json=salesRepName,proto3" json:"sales_rep_name,omitempty"`
+	DeliveryDate *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=delivery_date,json=deliveryDate,proto3" json:"delivery_date,omitempty"`
+	Notes        string                 `protobuf:"bytes,13,opt,name=notes,proto3" json:"notes,omitempty"`
+	// total_amount = subtotal_amount + vat_amount, all in currency
+	SubtotalAmount float64 `protobuf:"fixed64,14,opt,name=subtotal_amount,json=subtotalAmount,proto3" json:"subtotal_amount,omitempty"`
+	VatAmount      float64 `protobuf:"fixed64,15,opt,name=vat_amount,json=vatAmount,proto3" json:"vat_amount,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
@@ -2078,6 +2081,20 @@ func (x *Order) GetNotes() string {
 	return ""
 }
 
+func (x *Order) GetSubtotalAmount() float64 {
+	if x != nil {
+		return x.SubtotalAmount
+	}
+	return 0
+}
+
+func (x *Order) GetVatAmount() float64 {
+	if x != nil {
+		return x.VatAmount
+	}
+	return 0
+}
+
 type OrderItem struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
 	ProductId          string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
@@ -2088,8 +2105,11 @@ type OrderItem struct {
 	TotalPrice         float64                `protobuf:"fixed64,6,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
 	DiscountPercentage float64                `protobuf:"fixed64,7,opt,name=discount_percentage,json=discountPercentage,proto3" json:"discount_percentage,omitempty"`
 	DiscountAmount     float64                `protobuf:"fixed64,8,opt,name=discount_amount,json=discountAmount,proto3" json:"discount_amount,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	// unit_price is the catalog list price, total_price the line total after discount excluding VAT
+	VatRate       float64 `protobuf:"fixed64,9,opt,name=vat_rate,json=vatRate,proto3" json:"vat_rate,omitempty"`
+	VatAmount     float64 `protobuf:"fixed64,10,opt,name=vat_amount,json=vatAmount,proto3" json:"vat_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *OrderItem) Reset() {
@@ -2178,6 +2198,20 @@ func (x *OrderItem) GetDiscountAmount() float64 {
 	return 0
 }
 
+func (x *OrderItem) GetVatRate() float64 {
+	if x != nil {
+		return x.VatRate
+	}
+	return 0
+}
+
+func (x *OrderItem) GetVatAmount() float64 {
+	if x != nil {
+		return x.VatAmount
+	}
+	return 0
+}
+
 type PaymentInfo struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	Method          PaymentMethod          `protobuf:"varint,1,opt,name=method,proto3,enum=outlet.PaymentMethod" json:"method,omitempty"`
@@ -2362,6 +2396,7 @@ type OutletStatistics struct {
 	MonthlyRevenue          []*MonthlyRevenue      `protobuf:"bytes,11,rep,name=monthly_revenue,json=monthlyRevenue,proto3" json:"monthly_revenue,omitempty"`
 	Segment                 CustomerSegment        `protobuf:"varint,12,opt,name=segment,proto3,enum=outlet.CustomerSegment" json:"segment,omitempty"`
 	CreditInfo              *CreditInfo            `protobuf:"bytes,13,opt,name=credit_info,json=creditInfo,proto3" json:"credit_info,omitempty"`
+	Currency                string                 `protobuf:"bytes,14,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields           protoimpl.UnknownFields
 	sizeCache               protoimpl.SizeCache
 }
@@ -2487,6 +2522,13 @@ func (x *OutletStatistics) GetCreditInfo() *CreditInfo {
 	return nil
 }
 
+func (x *OutletStatistics) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type ProductStatistics struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
@@ -3443,7 +3485,7 @@ const file_proto_outlet_proto_rawDesc = "" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12&\n" +
 	"\x04type\x18\x03 \x01(\x0e2\x12.outlet.ActionTypeR\x04type\x12,\n" +
 	"\x06status\x18\x04 \x01(\x0e2\x14.outlet.ActionStatusR\x06status\x125\n" +
-	"\bdue_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\"\xa7\x04\n" +
+	"\bdue_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\"\xef\x04\n" +
 	"\x05Order\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12!\n" +
 	"\forder_number\x18\x02 \x01(\tR\vorderNumber\x129\n" +
@@ -3460,7 +3502,10 @@ const file_proto_outlet_proto_rawDesc = "" +
 	"salesRepId\x12$\n" +
 	"\x0esales_rep_name\x18\v \x01(\tR\fsalesRepName\x12?\n" +
 	"\rdelivery_date\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\fdeliveryDate\x12\x14\n" +
-	"\x05notes\x18\r \x01(\tR\x05notes\"\x95\x02\n" +
+	"\x05notes\x18\r \x01(\tR\x05notes\x12'\n" +
+	"\x0fsubtotal_amount\x18\x0e \x01(\x01R\x0esubtotalAmount\x12\x1d\n" +
+	"\n" +
+	"vat_amount\x18\x0f \x01(\x01R\tvatAmount\"\xcf\x02\n" +
 	"\tOrderItem\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12!\n" +
@@ -3472,7 +3517,11 @@ const file_proto_outlet_proto_rawDesc = "" +
 	"\vtotal_price\x18\x06 \x01(\x01R\n" +
 	"totalPrice\x12/\n" +
 	"\x13discount_percentage\x18\a \x01(\x01R\x12discountPercentage\x12'\n" +
-	"\x0fdiscount_amount\x18\b \x01(\x01R\x0ediscountAmount\"\x95\x02\n" +
+	"\x0fdiscount_amount\x18\b \x01(\x01R\x0ediscountAmount\x12\x19\n" +
+	"\bvat_rate\x18\t \x01(\x01R\avatRate\x12\x1d\n" +
+	"\n" +
+	"vat_amount\x18\n" +
+	" \x01(\x01R\tvatAmount\"\x95\x02\n" +
 	"\vPaymentInfo\x12-\n" +
 	"\x06method\x18\x01 \x01(\x0e2\x15.outlet.PaymentMethodR\x06method\x12-\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x15.outlet.PaymentStatusR\x06status\x12=\n" +
@@ -3489,7 +3538,7 @@ const file_proto_outlet_proto_rawDesc = "" +
 	"actualDate\x12.\n" +
 	"\x06status\x18\x04 \x01(\x0e2\x16.outlet.DeliveryStatusR\x06status\x12%\n" +
 	"\x0edelivery_notes\x18\x05 \x01(\tR\rdeliveryNotes\x12'\n" +
-	"\x0ftracking_number\x18\x06 \x01(\tR\x0etrackingNumber\"\xb7\x05\n" +
+	"\x0ftracking_number\x18\x06 \x01(\tR\x0etrackingNumber\"\xd3\x05\n" +
 	"\x10OutletStatistics\x12*\n" +
 	"\x11total_revenue_ytd\x18\x01 \x01(\x01R\x0ftotalRevenueYtd\x125\n" +
 	"\x17total_revenue_last_year\x18\x02 \x01(\x01R\x14totalRevenueLastYear\x12.\n" +
@@ -3505,7 +3554,8 @@ const file_proto_outlet_proto_rawDesc = "" +
 	"\x0fmonthly_revenue\x18\v \x03(\v2\x16.outlet.MonthlyRevenueR\x0emonthlyRevenue\x121\n" +
 	"\asegment\x18\f \x01(\x0e2\x17.outlet.CustomerSegmentR\asegment\x123\n" +
 	"\vcredit_info\x18\r \x01(\v2\x12.outlet.CreditInfoR\n" +
-	"creditInfo\"\xb7\x01\n" +
+	"creditInfo\x12\x1a\n" +
+	"\bcurrency\x18\x0e \x01(\tR\bcurrency\"\xb7\x01\n" +
 	"\x11ProductStatistics\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12!\n" +
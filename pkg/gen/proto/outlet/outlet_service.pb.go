@@ -0,0 +1,158 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: proto/outlet_service.proto
+
+package outlet
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamOutletsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OutletCount   int32                  `protobuf:"varint,1,opt,name=outlet_count,json=outletCount,proto3" json:"outlet_count,omitempty"` // number of outlets, defaults to 100
+	OutletId      string                 `protobuf:"bytes,2,opt,name=outlet_id,json=outletId,proto3" json:"outlet_id,omitempty"`           // ID of the generated outlets, defaults to outlet-001
+	Locale        string                 `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`                               // locale of the generated data, e.g. nl-NL
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`           // fields of OutletDetails to send, all when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOutletsRequest) Reset() {
+	*x = StreamOutletsRequest{}
+	mi := &file_proto_outlet_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOutletsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOutletsRequest) ProtoMessage() {}
+
+func (x *StreamOutletsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_outlet_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOutletsRequest.ProtoReflect.Descriptor instead.
+func (*StreamOutletsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_outlet_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamOutletsRequest) GetOutletCount() int32 {
+	if x != nil {
+		return x.OutletCount
+	}
+	return 0
+}
+
+func (x *StreamOutletsRequest) GetOutletId() string {
+	if x != nil {
+		return x.OutletId
+	}
+	return ""
+}
+
+func (x *StreamOutletsRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *StreamOutletsRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+var File_proto_outlet_service_proto protoreflect.FileDescriptor
+
+const file_proto_outlet_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1aproto/outlet_service.proto\x12\x06outlet\x1a google/protobuf/field_mask.proto\x1a\x12proto/outlet.proto\"\xa7\x01\n" +
+	"\x14StreamOutletsRequest\x12!\n" +
+	"\foutlet_count\x18\x01 \x01(\x05R\voutletCount\x12\x1b\n" +
+	"\toutlet_id\x18\x02 \x01(\tR\boutletId\x12\x16\n" +
+	"\x06locale\x18\x03 \x01(\tR\x06locale\x127\n" +
+	"\tread_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask2W\n" +
+	"\rOutletService\x12F\n" +
+	"\rStreamOutlets\x12\x1c.outlet.StreamOutletsRequest\x1a\x15.outlet.OutletDetails0\x01B$Z\"srv-eazle-advise-mock/proto/outletb\x06proto3"
+
+var (
+	file_proto_outlet_service_proto_rawDescOnce sync.Once
+	file_proto_outlet_service_proto_rawDescData []byte
+)
+
+func file_proto_outlet_service_proto_rawDescGZIP() []byte {
+	file_proto_outlet_service_proto_rawDescOnce.Do(func() {
+		file_proto_outlet_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_outlet_service_proto_rawDesc), len(file_proto_outlet_service_proto_rawDesc)))
+	})
+	return file_proto_outlet_service_proto_rawDescData
+}
+
+var file_proto_outlet_service_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_proto_outlet_service_proto_goTypes = []any{
+	(*StreamOutletsRequest)(nil),  // 0: outlet.StreamOutletsRequest
+	(*fieldmaskpb.FieldMask)(nil), // 1: google.protobuf.FieldMask
+	(*OutletDetails)(nil),         // 2: outlet.OutletDetails
+}
+var file_proto_outlet_service_proto_depIdxs = []int32{
+	1, // 0: outlet.StreamOutletsRequest.read_mask:type_name -> google.protobuf.FieldMask
+	0, // 1: outlet.OutletService.StreamOutlets:input_type -> outlet.StreamOutletsRequest
+	2, // 2: outlet.OutletService.StreamOutlets:output_type -> outlet.OutletDetails
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_outlet_service_proto_init() }
+func file_proto_outlet_service_proto_init() {
+	if File_proto_outlet_service_proto != nil {
+		return
+	}
+	file_proto_outlet_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_outlet_service_proto_rawDesc), len(file_proto_outlet_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_outlet_service_proto_goTypes,
+		DependencyIndexes: file_proto_outlet_service_proto_depIdxs,
+		MessageInfos:      file_proto_outlet_service_proto_msgTypes,
+	}.Build()
+	File_proto_outlet_service_proto = out.File
+	file_proto_outlet_service_proto_goTypes = nil
+	file_proto_outlet_service_proto_depIdxs = nil
+}
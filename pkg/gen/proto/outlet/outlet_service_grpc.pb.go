@@ -0,0 +1,130 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             v5.29.3
+// source: proto/outlet_service.proto
+
+package outlet
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OutletService_StreamOutlets_FullMethodName = "/outlet.OutletService/StreamOutlets"
+)
+
+// OutletServiceClient is the client API for OutletService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Bulk access to outlets, mirroring the backend's export API
+type OutletServiceClient interface {
+	// Streams outlets one message at a time
+	StreamOutlets(ctx context.Context, in *StreamOutletsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OutletDetails], error)
+}
+
+type outletServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOutletServiceClient(cc grpc.ClientConnInterface) OutletServiceClient {
+	return &outletServiceClient{cc}
+}
+
+func (c *outletServiceClient) StreamOutlets(ctx context.Context, in *StreamOutletsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OutletDetails], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OutletService_ServiceDesc.Streams[0], OutletService_StreamOutlets_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOutletsRequest, OutletDetails]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OutletService_StreamOutletsClient = grpc.ServerStreamingClient[OutletDetails]
+
+// OutletServiceServer is the server API for OutletService service.
+// All implementations must embed UnimplementedOutletServiceServer
+// for forward compatibility.
+//
+// Bulk access to outlets, mirroring the backend's export API
+type OutletServiceServer interface {
+	// Streams outlets one message at a time
+	StreamOutlets(*StreamOutletsRequest, grpc.ServerStreamingServer[OutletDetails]) error
+	mustEmbedUnimplementedOutletServiceServer()
+}
+
+// UnimplementedOutletServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOutletServiceServer struct{}
+
+func (UnimplementedOutletServiceServer) StreamOutlets(*StreamOutletsRequest, grpc.ServerStreamingServer[OutletDetails]) error {
+	return status.Error(codes.Unimplemented, "method StreamOutlets not implemented")
+}
+func (UnimplementedOutletServiceServer) mustEmbedUnimplementedOutletServiceServer() {}
+func (UnimplementedOutletServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeOutletServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OutletServiceServer will
+// result in compilation errors.
+type UnsafeOutletServiceServer interface {
+	mustEmbedUnimplementedOutletServiceServer()
+}
+
+func RegisterOutletServiceServer(s grpc.ServiceRegistrar, srv OutletServiceServer) {
+	// If the following call panics, it indicates UnimplementedOutletServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OutletService_ServiceDesc, srv)
+}
+
+func _OutletService_StreamOutlets_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOutletsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OutletServiceServer).StreamOutlets(m, &grpc.GenericServerStream[StreamOutletsRequest, OutletDetails]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OutletService_StreamOutletsServer = grpc.ServerStreamingServer[OutletDetails]
+
+// OutletService_ServiceDesc is the grpc.ServiceDesc for OutletService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OutletService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "outlet.OutletService",
+	HandlerType: (*OutletServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamOutlets",
+			Handler:       _OutletService_StreamOutlets_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/outlet_service.proto",
+}
@@ -0,0 +1,620 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: proto/outlet_sync.proto
+
+package outlet
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Kinds of records tracked by delta sync
+type SyncRecordType int32
+
+const (
+	SyncRecordType_SYNC_RECORD_TYPE_UNSPECIFIED    SyncRecordType = 0
+	SyncRecordType_SYNC_RECORD_TYPE_OUTLET         SyncRecordType = 1
+	SyncRecordType_SYNC_RECORD_TYPE_NOTE           SyncRecordType = 2
+	SyncRecordType_SYNC_RECORD_TYPE_VISIT          SyncRecordType = 3
+	SyncRecordType_SYNC_RECORD_TYPE_ORDER          SyncRecordType = 4
+	SyncRecordType_SYNC_RECORD_TYPE_CHECKLIST_ITEM SyncRecordType = 5
+)
+
+// Enum value maps for SyncRecordType.
+var (
+	SyncRecordType_name = map[int32]string{
+		0: "SYNC_RECORD_TYPE_UNSPECIFIED",
+		1: "SYNC_RECORD_TYPE_OUTLET",
+		2: "SYNC_RECORD_TYPE_NOTE",
+		3: "SYNC_RECORD_TYPE_VISIT",
+		4: "SYNC_RECORD_TYPE_ORDER",
+		5: "SYNC_RECORD_TYPE_CHECKLIST_ITEM",
+	}
+	SyncRecordType_value = map[string]int32{
+		"SYNC_RECORD_TYPE_UNSPECIFIED":    0,
+		"SYNC_RECORD_TYPE_OUTLET":         1,
+		"SYNC_RECORD_TYPE_NOTE":           2,
+		"SYNC_RECORD_TYPE_VISIT":          3,
+		"SYNC_RECORD_TYPE_ORDER":          4,
+		"SYNC_RECORD_TYPE_CHECKLIST_ITEM": 5,
+	}
+)
+
+func (x SyncRecordType) Enum() *SyncRecordType {
+	p := new(SyncRecordType)
+	*p = x
+	return p
+}
+
+func (x SyncRecordType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SyncRecordType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_outlet_sync_proto_enumTypes[0].Descriptor()
+}
+
+func (SyncRecordType) Type() protoreflect.EnumType {
+	return &file_proto_outlet_sync_proto_enumTypes[0]
+}
+
+func (x SyncRecordType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SyncRecordType.Descriptor instead.
+func (SyncRecordType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_outlet_sync_proto_rawDescGZIP(), []int{0}
+}
+
+// Changes since a sync token, returned by GET /sync/outlets
+type SyncOutletsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Outlets       []*OutletDetails       `protobuf:"bytes,1,rep,name=outlets,proto3" json:"outlets,omitempty"` // outlets created or updated, complete with their records
+	Notes         []*NoteChange          `protobuf:"bytes,2,rep,name=notes,proto3" json:"notes,omitempty"`     // records created or updated in outlets not listed above
+	Visits        []*VisitChange         `protobuf:"bytes,3,rep,name=visits,proto3" json:"visits,omitempty"`
+	Orders        []*OrderChange         `protobuf:"bytes,4,rep,name=orders,proto3" json:"orders,omitempty"`
+	Checklist     []*ChecklistItemChange `protobuf:"bytes,5,rep,name=checklist,proto3" json:"checklist,omitempty"`
+	Deleted       []*Tombstone           `protobuf:"bytes,6,rep,name=deleted,proto3" json:"deleted,omitempty"`                      // outlets and records deleted since the token
+	NextToken     string                 `protobuf:"bytes,7,opt,name=next_token,json=nextToken,proto3" json:"next_token,omitempty"` // pass as since to get the changes after this response
+	Full          bool                   `protobuf:"varint,8,opt,name=full,proto3" json:"full,omitempty"`                           // true when outlets is the complete universe, replacing local data
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncOutletsResponse) Reset() {
+	*x = SyncOutletsResponse{}
+	mi := &file_proto_outlet_sync_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncOutletsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOutletsResponse) ProtoMessage() {}
+
+func (x *SyncOutletsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_outlet_sync_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOutletsResponse.ProtoReflect.Descriptor instead.
+func (*SyncOutletsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_outlet_sync_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SyncOutletsResponse) GetOutlets() []*OutletDetails {
+	if x != nil {
+		return x.Outlets
+	}
+	return nil
+}
+
+func (x *SyncOutletsResponse) GetNotes() []*NoteChange {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *SyncOutletsResponse) GetVisits() []*VisitChange {
+	if x != nil {
+		return x.Visits
+	}
+	return nil
+}
+
+func (x *SyncOutletsResponse) GetOrders() []*OrderChange {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *SyncOutletsResponse) GetChecklist() []*ChecklistItemChange {
+	if x != nil {
+		return x.Checklist
+	}
+	return nil
+}
+
+func (x *SyncOutletsResponse) GetDeleted() []*Tombstone {
+	if x != nil {
+		return x.Deleted
+	}
+	return nil
+}
+
+func (x *SyncOutletsResponse) GetNextToken() string {
+	if x != nil {
+		return x.NextToken
+	}
+	return ""
+}
+
+func (x *SyncOutletsResponse) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+type NoteChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OutletId      string                 `protobuf:"bytes,1,opt,name=outlet_id,json=outletId,proto3" json:"outlet_id,omitempty"`
+	Note          *Note                  `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteChange) Reset() {
+	*x = NoteChange{}
+	mi := &file_proto_outlet_sync_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteChange) ProtoMessage() {}
+
+func (x *NoteChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_outlet_sync_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteChange.ProtoReflect.Descriptor instead.
+func (*NoteChange) Descriptor() ([]byte, []int) {
+	return file_proto_outlet_sync_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NoteChange) GetOutletId() string {
+	if x != nil {
+		return x.OutletId
+	}
+	return ""
+}
+
+func (x *NoteChange) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *NoteChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type VisitChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OutletId      string                 `protobuf:"bytes,1,opt,name=outlet_id,json=outletId,proto3" json:"outlet_id,omitempty"`
+	Visit         *Visit                 `protobuf:"bytes,2,opt,name=visit,proto3" json:"visit,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VisitChange) Reset() {
+	*x = VisitChange{}
+	mi := &file_proto_outlet_sync_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VisitChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VisitChange) ProtoMessage() {}
+
+func (x *VisitChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_outlet_sync_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VisitChange.ProtoReflect.Descriptor instead.
+func (*VisitChange) Descriptor() ([]byte, []int) {
+	return file_proto_outlet_sync_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VisitChange) GetOutletId() string {
+	if x != nil {
+		return x.OutletId
+	}
+	return ""
+}
+
+func (x *VisitChange) GetVisit() *Visit {
+	if x != nil {
+		return x.Visit
+	}
+	return nil
+}
+
+func (x *VisitChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type OrderChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OutletId      string                 `protobuf:"bytes,1,opt,name=outlet_id,json=outletId,proto3" json:"outlet_id,omitempty"`
+	Order         *Order                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderChange) Reset() {
+	*x = OrderChange{}
+	mi := &file_proto_outlet_sync_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderChange) ProtoMessage() {}
+
+func (x *OrderChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_outlet_sync_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderChange.ProtoReflect.Descriptor instead.
+func (*OrderChange) Descriptor() ([]byte, []int) {
+	return file_proto_outlet_sync_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderChange) GetOutletId() string {
+	if x != nil {
+		return x.OutletId
+	}
+	return ""
+}
+
+func (x *OrderChange) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type ChecklistItemChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OutletId      string                 `protobuf:"bytes,1,opt,name=outlet_id,json=outletId,proto3" json:"outlet_id,omitempty"`
+	Item          *ChecklistItem         `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChecklistItemChange) Reset() {
+	*x = ChecklistItemChange{}
+	mi := &file_proto_outlet_sync_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChecklistItemChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecklistItemChange) ProtoMessage() {}
+
+func (x *ChecklistItemChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_outlet_sync_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecklistItemChange.ProtoReflect.Descriptor instead.
+func (*ChecklistItemChange) Descriptor() ([]byte, []int) {
+	return file_proto_outlet_sync_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChecklistItemChange) GetOutletId() string {
+	if x != nil {
+		return x.OutletId
+	}
+	return ""
+}
+
+func (x *ChecklistItemChange) GetItem() *ChecklistItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *ChecklistItemChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type Tombstone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          SyncRecordType         `protobuf:"varint,1,opt,name=type,proto3,enum=outlet.SyncRecordType" json:"type,omitempty"`
+	OutletId      string                 `protobuf:"bytes,2,opt,name=outlet_id,json=outletId,proto3" json:"outlet_id,omitempty"`
+	RecordId      string                 `protobuf:"bytes,3,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"` // empty for outlets
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tombstone) Reset() {
+	*x = Tombstone{}
+	mi := &file_proto_outlet_sync_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tombstone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tombstone) ProtoMessage() {}
+
+func (x *Tombstone) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_outlet_sync_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tombstone.ProtoReflect.Descriptor instead.
+func (*Tombstone) Descriptor() ([]byte, []int) {
+	return file_proto_outlet_sync_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Tombstone) GetType() SyncRecordType {
+	if x != nil {
+		return x.Type
+	}
+	return SyncRecordType_SYNC_RECORD_TYPE_UNSPECIFIED
+}
+
+func (x *Tombstone) GetOutletId() string {
+	if x != nil {
+		return x.OutletId
+	}
+	return ""
+}
+
+func (x *Tombstone) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *Tombstone) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+var File_proto_outlet_sync_proto protoreflect.FileDescriptor
+
+const file_proto_outlet_sync_proto_rawDesc = "" +
+	"\n" +
+	"\x17proto/outlet_sync.proto\x12\x06outlet\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x12proto/outlet.proto\"\xe5\x02\n" +
+	"\x13SyncOutletsResponse\x12/\n" +
+	"\aoutlets\x18\x01 \x03(\v2\x15.outlet.OutletDetailsR\aoutlets\x12(\n" +
+	"\x05notes\x18\x02 \x03(\v2\x12.outlet.NoteChangeR\x05notes\x12+\n" +
+	"\x06visits\x18\x03 \x03(\v2\x13.outlet.VisitChangeR\x06visits\x12+\n" +
+	"\x06orders\x18\x04 \x03(\v2\x13.outlet.OrderChangeR\x06orders\x129\n" +
+	"\tchecklist\x18\x05 \x03(\v2\x1b.outlet.ChecklistItemChangeR\tchecklist\x12+\n" +
+	"\adeleted\x18\x06 \x03(\v2\x11.outlet.TombstoneR\adeleted\x12\x1d\n" +
+	"\n" +
+	"next_token\x18\a \x01(\tR\tnextToken\x12\x12\n" +
+	"\x04full\x18\b \x01(\bR\x04full\"\x86\x01\n" +
+	"\n" +
+	"NoteChange\x12\x1b\n" +
+	"\toutlet_id\x18\x01 \x01(\tR\boutletId\x12 \n" +
+	"\x04note\x18\x02 \x01(\v2\f.outlet.NoteR\x04note\x129\n" +
+	"\n" +
+	"changed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"\x8a\x01\n" +
+	"\vVisitChange\x12\x1b\n" +
+	"\toutlet_id\x18\x01 \x01(\tR\boutletId\x12#\n" +
+	"\x05visit\x18\x02 \x01(\v2\r.outlet.VisitR\x05visit\x129\n" +
+	"\n" +
+	"changed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"\x8a\x01\n" +
+	"\vOrderChange\x12\x1b\n" +
+	"\toutlet_id\x18\x01 \x01(\tR\boutletId\x12#\n" +
+	"\x05order\x18\x02 \x01(\v2\r.outlet.OrderR\x05order\x129\n" +
+	"\n" +
+	"changed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"\x98\x01\n" +
+	"\x13ChecklistItemChange\x12\x1b\n" +
+	"\toutlet_id\x18\x01 \x01(\tR\boutletId\x12)\n" +
+	"\x04item\x18\x02 \x01(\v2\x15.outlet.ChecklistItemR\x04item\x129\n" +
+	"\n" +
+	"changed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"\xac\x01\n" +
+	"\tTombstone\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.outlet.SyncRecordTypeR\x04type\x12\x1b\n" +
+	"\toutlet_id\x18\x02 \x01(\tR\boutletId\x12\x1b\n" +
+	"\trecord_id\x18\x03 \x01(\tR\brecordId\x129\n" +
+	"\n" +
+	"deleted_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt*\xc7\x01\n" +
+	"\x0eSyncRecordType\x12 \n" +
+	"\x1cSYNC_RECORD_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17SYNC_RECORD_TYPE_OUTLET\x10\x01\x12\x19\n" +
+	"\x15SYNC_RECORD_TYPE_NOTE\x10\x02\x12\x1a\n" +
+	"\x16SYNC_RECORD_TYPE_VISIT\x10\x03\x12\x1a\n" +
+	"\x16SYNC_RECORD_TYPE_ORDER\x10\x04\x12#\n" +
+	"\x1fSYNC_RECORD_TYPE_CHECKLIST_ITEM\x10\x05B$Z\"srv-eazle-advise-mock/proto/outletb\x06proto3"
+
+var (
+	file_proto_outlet_sync_proto_rawDescOnce sync.Once
+	file_proto_outlet_sync_proto_rawDescData []byte
+)
+
+func file_proto_outlet_sync_proto_rawDescGZIP() []byte {
+	file_proto_outlet_sync_proto_rawDescOnce.Do(func() {
+		file_proto_outlet_sync_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_outlet_sync_proto_rawDesc), len(file_proto_outlet_sync_proto_rawDesc)))
+	})
+	return file_proto_outlet_sync_proto_rawDescData
+}
+
+var file_proto_outlet_sync_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_outlet_sync_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_outlet_sync_proto_goTypes = []any{
+	(SyncRecordType)(0),           // 0: outlet.SyncRecordType
+	(*SyncOutletsResponse)(nil),   // 1: outlet.SyncOutletsResponse
+	(*NoteChange)(nil),            // 2: outlet.NoteChange
+	(*VisitChange)(nil),           // 3: outlet.VisitChange
+	(*OrderChange)(nil),           // 4: outlet.OrderChange
+	(*ChecklistItemChange)(nil),   // 5: outlet.ChecklistItemChange
+	(*Tombstone)(nil),             // 6: outlet.Tombstone
+	(*OutletDetails)(nil),         // 7: outlet.OutletDetails
+	(*Note)(nil),                  // 8: outlet.Note
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*Visit)(nil),                 // 10: outlet.Visit
+	(*Order)(nil),                 // 11: outlet.Order
+	(*ChecklistItem)(nil),         // 12: outlet.ChecklistItem
+}
+var file_proto_outlet_sync_proto_depIdxs = []int32{
+	7,  // 0: outlet.SyncOutletsResponse.outlets:type_name -> outlet.OutletDetails
+	2,  // 1: outlet.SyncOutletsResponse.notes:type_name -> outlet.NoteChange
+	3,  // 2: outlet.SyncOutletsResponse.visits:type_name -> outlet.VisitChange
+	4,  // 3: outlet.SyncOutletsResponse.orders:type_name -> outlet.OrderChange
+	5,  // 4: outlet.SyncOutletsResponse.checklist:type_name -> outlet.ChecklistItemChange
+	6,  // 5: outlet.SyncOutletsResponse.deleted:type_name -> outlet.Tombstone
+	8,  // 6: outlet.NoteChange.note:type_name -> outlet.Note
+	9,  // 7: outlet.NoteChange.changed_at:type_name -> google.protobuf.Timestamp
+	10, // 8: outlet.VisitChange.visit:type_name -> outlet.Visit
+	9,  // 9: outlet.VisitChange.changed_at:type_name -> google.protobuf.Timestamp
+	11, // 10: outlet.OrderChange.order:type_name -> outlet.Order
+	9,  // 11: outlet.OrderChange.changed_at:type_name -> google.protobuf.Timestamp
+	12, // 12: outlet.ChecklistItemChange.item:type_name -> outlet.ChecklistItem
+	9,  // 13: outlet.ChecklistItemChange.changed_at:type_name -> google.protobuf.Timestamp
+	0,  // 14: outlet.Tombstone.type:type_name -> outlet.SyncRecordType
+	9,  // 15: outlet.Tombstone.deleted_at:type_name -> google.protobuf.Timestamp
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_proto_outlet_sync_proto_init() }
+func file_proto_outlet_sync_proto_init() {
+	if File_proto_outlet_sync_proto != nil {
+		return
+	}
+	file_proto_outlet_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_outlet_sync_proto_rawDesc), len(file_proto_outlet_sync_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_outlet_sync_proto_goTypes,
+		DependencyIndexes: file_proto_outlet_sync_proto_depIdxs,
+		EnumInfos:         file_proto_outlet_sync_proto_enumTypes,
+		MessageInfos:      file_proto_outlet_sync_proto_msgTypes,
+	}.Build()
+	File_proto_outlet_sync_proto = out.File
+	file_proto_outlet_sync_proto_goTypes = nil
+	file_proto_outlet_sync_proto_depIdxs = nil
+}
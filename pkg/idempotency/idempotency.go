@@ -0,0 +1,131 @@
+package idempotency
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response is the stored result of a write, replayed for its retries
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Outcome says how to answer a request carrying an Idempotency-Key
+type Outcome int
+
+const (
+	Process    Outcome = iota // first request with the key, process it and Complete or Abandon it
+	Replay                    // a retry, answer with the stored response
+	Mismatch                  // the key was used for a different request
+	InProgress                // the first request with the key is still being processed
+)
+
+type entry struct {
+	key         string
+	fingerprint [sha256.Size]byte
+	response    *Response // nil while in progress
+	created     time.Time
+}
+
+// Store remembers the results of writes by idempotency key for ttl, keeping
+// at most maxEntries
+type Store struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	order      *list.List // oldest first
+	entries    map[string]*list.Element
+}
+
+func New(maxEntries int, ttl time.Duration) *Store {
+	return &Store{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+// Fingerprint identifies a request so a reused key with another request is detected
+func Fingerprint(method, path string, body []byte) [sha256.Size]byte {
+	return sha256.Sum256(append([]byte(method+" "+path+"\n"), body...))
+}
+
+// Begin looks up a key. For Process the key is reserved until Complete or Abandon.
+func (s *Store) Begin(key string, fingerprint [sha256.Size]byte) (Outcome, Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	if element, ok := s.entries[key]; ok {
+		e := element.Value.(*entry)
+		switch {
+		case e.fingerprint != fingerprint:
+			return Mismatch, Response{}
+		case e.response == nil:
+			return InProgress, Response{}
+		default:
+			return Replay, *e.response
+		}
+	}
+
+	s.entries[key] = s.order.PushBack(&entry{key: key, fingerprint: fingerprint, created: time.Now()})
+	for s.maxEntries > 0 && s.order.Len() > s.maxEntries {
+		s.remove(s.order.Front())
+	}
+	return Process, Response{}
+}
+
+// Complete stores the response of the request that reserved key
+func (s *Store) Complete(key string, response Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		element.Value.(*entry).response = &response
+	}
+}
+
+// Abandon releases a key without a result, so a retry is processed again
+func (s *Store) Abandon(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+}
+
+// Clear forgets every key
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.order.Init()
+	s.entries = map[string]*list.Element{}
+}
+
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// expire drops the keys older than ttl, the caller holds the lock
+func (s *Store) expire() {
+	for element := s.order.Front(); element != nil; element = s.order.Front() {
+		if time.Since(element.Value.(*entry).created) < s.ttl {
+			return
+		}
+		s.remove(element)
+	}
+}
+
+func (s *Store) remove(element *list.Element) {
+	delete(s.entries, s.order.Remove(element).(*entry).key)
+}
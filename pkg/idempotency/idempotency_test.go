@@ -0,0 +1,93 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBegin(t *testing.T) {
+	create := Fingerprint("POST", "/outlets/outlet-001/notes", []byte(`{"title":"a"}`))
+	other := Fingerprint("POST", "/outlets/outlet-001/notes", []byte(`{"title":"b"}`))
+	created := Response{Status: http.StatusCreated, Body: []byte(`{"id":"note-1"}`)}
+
+	type call struct {
+		action      string // begin, complete or abandon
+		fingerprint [32]byte
+		want        Outcome
+	}
+	tests := []struct {
+		name  string
+		calls []call
+	}{
+		{name: "first request is processed", calls: []call{
+			{"begin", create, Process},
+		}},
+		{name: "retry while in progress", calls: []call{
+			{"begin", create, Process},
+			{"begin", create, InProgress},
+		}},
+		{name: "retry after completion is replayed", calls: []call{
+			{"begin", create, Process},
+			{action: "complete"},
+			{"begin", create, Replay},
+		}},
+		{name: "another request under the key", calls: []call{
+			{"begin", create, Process},
+			{action: "complete"},
+			{"begin", other, Mismatch},
+		}},
+		{name: "another request while in progress", calls: []call{
+			{"begin", create, Process},
+			{"begin", other, Mismatch},
+		}},
+		{name: "abandoned key is processed again", calls: []call{
+			{"begin", create, Process},
+			{action: "abandon"},
+			{"begin", other, Process},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := New(10, time.Hour)
+			for i, c := range tt.calls {
+				switch c.action {
+				case "complete":
+					store.Complete("key", created)
+				case "abandon":
+					store.Abandon("key")
+				default:
+					outcome, response := store.Begin("key", c.fingerprint)
+					if outcome != c.want {
+						t.Fatalf("call %d: outcome %d, want %d", i+1, outcome, c.want)
+					}
+					if outcome == Replay && response.Status != created.Status {
+						t.Errorf("call %d: replayed status %d, want %d", i+1, response.Status, created.Status)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBeginEvicts(t *testing.T) {
+	fingerprint := Fingerprint("POST", "/outlets", nil)
+
+	bounded := New(2, time.Hour)
+	for _, key := range []string{"a", "b", "c"} {
+		bounded.Begin(key, fingerprint)
+	}
+	if n := bounded.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+	if outcome, _ := bounded.Begin("a", fingerprint); outcome != Process {
+		t.Errorf("oldest key after eviction: outcome %d, want Process", outcome)
+	}
+
+	expiring := New(10, time.Millisecond)
+	expiring.Begin("a", fingerprint)
+	time.Sleep(5 * time.Millisecond)
+	if outcome, _ := expiring.Begin("a", fingerprint); outcome != Process {
+		t.Errorf("expired key: outcome %d, want Process", outcome)
+	}
+}
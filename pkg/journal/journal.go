@@ -0,0 +1,159 @@
+package journal
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a request the mock received together with how it was answered
+type Entry struct {
+	ID         int                 `json:"id"`
+	Time       time.Time           `json:"time"`
+	Method     string              `json:"method"`
+	Path       string              `json:"path"`
+	Query      string              `json:"query,omitempty"`
+	Headers    map[string][]string `json:"headers"`
+	Body       string              `json:"body,omitempty"`
+	BodyBase64 string              `json:"bodyBase64,omitempty"` // set instead of Body for binary bodies
+	Route      string              `json:"route,omitempty"`      // mux pattern that handled the request
+	Status     int                 `json:"status"`
+	LatencyMs  float64             `json:"latencyMs"`
+}
+
+// Filter selects entries, empty fields match everything
+type Filter struct {
+	Method     string            `json:"method,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	PathPrefix string            `json:"pathPrefix,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"` // header name -> exact value, "" only requires presence
+}
+
+func (f Filter) Matches(entry Entry) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, entry.Method) {
+		return false
+	}
+	if f.Path != "" && f.Path != entry.Path {
+		return false
+	}
+	if f.PathPrefix != "" && !strings.HasPrefix(entry.Path, f.PathPrefix) {
+		return false
+	}
+	for name, want := range f.Headers {
+		values, ok := headerValues(entry.Headers, name)
+		if !ok {
+			return false
+		}
+		if want != "" && !contains(values, want) {
+			return false
+		}
+	}
+	return true
+}
+
+func headerValues(headers map[string][]string, name string) ([]string, bool) {
+	for key, values := range headers {
+		if strings.EqualFold(key, name) {
+			return values, true
+		}
+	}
+	return nil, false
+}
+
+func contains(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Journal keeps the last entries in a ring buffer and optionally appends
+// every entry to a JSON Lines file
+type Journal struct {
+	mu      sync.Mutex
+	entries []Entry
+	start   int // index of the oldest entry once the buffer is full
+	size    int
+	nextID  int
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// New creates a journal that keeps the last size entries
+func New(size int) *Journal {
+	return &Journal{size: max(size, 1)}
+}
+
+// AppendTo additionally writes every entry to path, one JSON object per line
+func (j *Journal) AppendTo(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.file = file
+	j.encoder = json.NewEncoder(file)
+	return nil
+}
+
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	return j.file.Close()
+}
+
+// Record stores an entry, assigning its ID
+func (j *Journal) Record(entry Entry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.nextID++
+	entry.ID = j.nextID
+	if len(j.entries) < j.size {
+		j.entries = append(j.entries, entry)
+	} else {
+		j.entries[j.start] = entry
+		j.start = (j.start + 1) % j.size
+	}
+
+	if j.encoder != nil {
+		j.encoder.Encode(entry)
+	}
+}
+
+// Find returns the entries matching filter, oldest first
+func (j *Journal) Find(filter Filter) []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	found := []Entry{}
+	for i := range j.entries {
+		entry := j.entries[(j.start+i)%len(j.entries)]
+		if filter.Matches(entry) {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// Count returns how many entries match filter
+func (j *Journal) Count(filter Filter) int {
+	return len(j.Find(filter))
+}
+
+// Reset drops all entries, the file is left untouched
+func (j *Journal) Reset() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = nil
+	j.start = 0
+}
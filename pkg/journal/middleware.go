@@ -0,0 +1,100 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxBodySize caps how much of a request body is kept in the journal
+const maxBodySize = 64 << 10
+
+// statusRecorder remembers the status code written by the handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(p []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(p)
+}
+
+// RecordStatus keeps the status of a response written past WriteHeader, see fault.StatusRecorder
+func (s *statusRecorder) RecordStatus(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+}
+
+// Flush forwards to the underlying writer so throttled and streamed bodies
+// still reach the client as they are written
+func (s *statusRecorder) Flush() {
+	http.NewResponseController(s.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// Handler records every request served by next, except the admin API under
+// /__admin/. The route of an entry is the pattern of mux that matches the
+// request, or the stub mapping that answered it.
+func (j *Journal) Handler(next http.Handler, mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/__admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var body []byte
+		if r.Body != nil {
+			body, _ = io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+			// Hand the handler the full body again, including anything past the cap
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		}
+
+		entry := Entry{
+			Time:    time.Now(),
+			Method:  r.Method,
+			Path:    r.URL.Path,
+			Query:   r.URL.RawQuery,
+			Headers: r.Header.Clone(),
+		}
+		if utf8.Valid(body) {
+			entry.Body = string(body)
+		} else {
+			entry.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+		}
+		_, entry.Route = mux.Handler(r)
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		// Network faults abort the handler with a panic, the request still counts
+		defer func() {
+			entry.Status = recorder.status
+			if mapping := w.Header().Get("X-Stub-Mapping"); mapping != "" {
+				entry.Route = "stub:" + mapping
+			}
+			entry.LatencyMs = float64(time.Since(entry.Time).Microseconds()) / 1000
+			j.Record(entry)
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
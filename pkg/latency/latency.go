@@ -0,0 +1,258 @@
+package latency
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DistributionFixed      = "fixed"
+	DistributionUniform    = "uniform"
+	DistributionNormal     = "normal"
+	DistributionPercentile = "percentile"
+)
+
+// Profile describes how long a response takes before its first byte and how
+// fast its body is sent afterwards. Which fields are used depends on Distribution:
+//   - fixed: FixedMs
+//   - uniform: MinMs to MaxMs
+//   - normal: MeanMs and StdDevMs, never below zero
+//   - percentile: P50Ms, P90Ms and P99Ms, for long-tail latencies
+//
+// BandwidthKbps throttles the body, 0 means unlimited.
+type Profile struct {
+	Name          string `json:"name"`
+	Distribution  string `json:"distribution"`
+	FixedMs       int    `json:"fixedMs,omitempty"`
+	MinMs         int    `json:"minMs,omitempty"`
+	MaxMs         int    `json:"maxMs,omitempty"`
+	MeanMs        int    `json:"meanMs,omitempty"`
+	StdDevMs      int    `json:"stdDevMs,omitempty"`
+	P50Ms         int    `json:"p50Ms,omitempty"`
+	P90Ms         int    `json:"p90Ms,omitempty"`
+	P99Ms         int    `json:"p99Ms,omitempty"`
+	BandwidthKbps int    `json:"bandwidthKbps,omitempty"`
+}
+
+// BuiltinProfiles are rough approximations of common mobile and office networks
+var BuiltinProfiles = []Profile{
+	{Name: "office-wifi", Distribution: DistributionPercentile, P50Ms: 20, P90Ms: 45, P99Ms: 120, BandwidthKbps: 50000},
+	{Name: "4g", Distribution: DistributionPercentile, P50Ms: 80, P90Ms: 150, P99Ms: 400, BandwidthKbps: 10000},
+	{Name: "3g", Distribution: DistributionPercentile, P50Ms: 300, P90Ms: 600, P99Ms: 1500, BandwidthKbps: 750},
+	{Name: "edge", Distribution: DistributionPercentile, P50Ms: 800, P90Ms: 1500, P99Ms: 3000, BandwidthKbps: 200},
+}
+
+func (p Profile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	switch p.Distribution {
+	case DistributionFixed:
+		if p.FixedMs < 0 {
+			return fmt.Errorf("fixedMs must not be negative")
+		}
+	case DistributionUniform:
+		if p.MinMs < 0 || p.MaxMs < p.MinMs {
+			return fmt.Errorf("uniform needs 0 <= minMs <= maxMs")
+		}
+	case DistributionNormal:
+		if p.MeanMs < 0 || p.StdDevMs < 0 {
+			return fmt.Errorf("meanMs and stdDevMs must not be negative")
+		}
+	case DistributionPercentile:
+		if p.P50Ms < 0 || p.P90Ms < p.P50Ms || p.P99Ms < p.P90Ms {
+			return fmt.Errorf("percentile needs 0 <= p50Ms <= p90Ms <= p99Ms")
+		}
+	default:
+		return fmt.Errorf("unknown distribution %q", p.Distribution)
+	}
+	if p.BandwidthKbps < 0 {
+		return fmt.Errorf("bandwidthKbps must not be negative")
+	}
+	return nil
+}
+
+// Delay samples the time to wait before the response is written
+func (p Profile) Delay() time.Duration {
+	var ms float64
+	switch p.Distribution {
+	case DistributionFixed:
+		ms = float64(p.FixedMs)
+	case DistributionUniform:
+		ms = float64(p.MinMs) + rand.Float64()*float64(p.MaxMs-p.MinMs)
+	case DistributionNormal:
+		ms = math.Max(0, rand.NormFloat64()*float64(p.StdDevMs)+float64(p.MeanMs))
+	case DistributionPercentile:
+		ms = p.samplePercentile(rand.Float64())
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// samplePercentile interpolates linearly between the configured percentiles,
+// with the fastest responses at half the median and the slowest at 1.5x p99
+func (p Profile) samplePercentile(u float64) float64 {
+	points := []struct{ quantile, ms float64 }{
+		{0, float64(p.P50Ms) / 2},
+		{0.5, float64(p.P50Ms)},
+		{0.9, float64(p.P90Ms)},
+		{0.99, float64(p.P99Ms)},
+		{1, float64(p.P99Ms) * 1.5},
+	}
+	for i := 1; i < len(points); i++ {
+		if u <= points[i].quantile {
+			lo, hi := points[i-1], points[i]
+			return lo.ms + (u-lo.quantile)/(hi.quantile-lo.quantile)*(hi.ms-lo.ms)
+		}
+	}
+	return points[len(points)-1].ms
+}
+
+// Config is the file format of -latency and the admin API
+type Config struct {
+	Profiles []Profile         `json:"profiles,omitempty"`
+	Routes   map[string]string `json:"routes,omitempty"` // path prefix -> profile name
+}
+
+// Registry holds the known profiles and which routes use them
+type Registry struct {
+	mu       sync.RWMutex
+	profiles map[string]Profile
+	routes   map[string]string
+}
+
+func NewRegistry() *Registry {
+	registry := &Registry{
+		profiles: map[string]Profile{},
+		routes:   map[string]string{},
+	}
+	for _, profile := range BuiltinProfiles {
+		registry.profiles[profile.Name] = profile
+	}
+	return registry
+}
+
+// Apply adds the profiles and route bindings of config, replacing existing ones
+// with the same name or route. An empty profile name removes a route binding.
+func (r *Registry) Apply(config Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, profile := range config.Profiles {
+		if err := profile.Validate(); err != nil {
+			return fmt.Errorf("profile %q: %w", profile.Name, err)
+		}
+	}
+	for route, name := range config.Routes {
+		if name == "" {
+			continue
+		}
+		if _, ok := r.profiles[name]; !ok && !hasProfile(config.Profiles, name) {
+			return fmt.Errorf("route %q: unknown profile %q", route, name)
+		}
+	}
+
+	for _, profile := range config.Profiles {
+		r.profiles[profile.Name] = profile
+	}
+	for route, name := range config.Routes {
+		if name == "" {
+			delete(r.routes, route)
+		} else {
+			r.routes[route] = name
+		}
+	}
+	return nil
+}
+
+func hasProfile(profiles []Profile, name string) bool {
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadFile applies a Config stored as JSON
+func (r *Registry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := r.Apply(config); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Config returns all profiles and route bindings
+func (r *Registry) Config() Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	config := Config{Routes: map[string]string{}}
+	for _, profile := range r.profiles {
+		config.Profiles = append(config.Profiles, profile)
+	}
+	sort.Slice(config.Profiles, func(i, j int) bool { return config.Profiles[i].Name < config.Profiles[j].Name })
+	for route, name := range r.routes {
+		config.Routes[route] = name
+	}
+	return config
+}
+
+// Profile looks up a profile by name
+func (r *Registry) Profile(name string) (Profile, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	profile, ok := r.profiles[name]
+	return profile, ok
+}
+
+// Reset drops custom profiles and all route bindings
+func (r *Registry) Reset() {
+	fresh := NewRegistry()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.profiles = fresh.profiles
+	r.routes = fresh.routes
+}
+
+// Select picks the profile for a request: the X-Latency-Profile header wins,
+// otherwise the binding with the longest matching route prefix is used
+func (r *Registry) Select(req *http.Request) (Profile, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if name := req.Header.Get("X-Latency-Profile"); name != "" {
+		profile, ok := r.profiles[name]
+		if !ok {
+			return Profile{}, false, fmt.Errorf("Unknown latency profile %q", name)
+		}
+		return profile, true, nil
+	}
+
+	bestRoute := ""
+	for route := range r.routes {
+		if strings.HasPrefix(req.URL.Path, route) && len(route) > len(bestRoute) {
+			bestRoute = route
+		}
+	}
+	if bestRoute == "" {
+		return Profile{}, false, nil
+	}
+	return r.profiles[r.routes[bestRoute]], true, nil
+}
@@ -0,0 +1,72 @@
+package latency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Sleep waits for d or until ctx is done, reporting whether the full duration passed
+func Sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// throttledWriter sends the body in small flushed chunks so the client sees it
+// arrive at the configured bandwidth instead of all at once
+type throttledWriter struct {
+	http.ResponseWriter
+	ctx            context.Context
+	bytesPerSecond int
+}
+
+// Throttle limits the body written to w to kbps kilobits per second.
+// Writing stops early when ctx is cancelled.
+func Throttle(ctx context.Context, w http.ResponseWriter, kbps int) http.ResponseWriter {
+	if kbps <= 0 {
+		return w
+	}
+	return &throttledWriter{
+		ResponseWriter: w,
+		ctx:            ctx,
+		bytesPerSecond: kbps * 1000 / 8,
+	}
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	// 50ms worth of data per chunk keeps the stream smooth without a syscall per byte
+	chunkSize := max(t.bytesPerSecond/20, 1)
+
+	written := 0
+	for len(p) > 0 {
+		n := min(chunkSize, len(p))
+		m, err := t.ResponseWriter.Write(p[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		// Reaches through wrappers that only provide Unwrap
+		http.NewResponseController(t.ResponseWriter).Flush()
+		p = p[n:]
+
+		if !Sleep(t.ctx, time.Duration(n)*time.Second/time.Duration(t.bytesPerSecond)) {
+			return written, t.ctx.Err()
+		}
+	}
+	return written, nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (t *throttledWriter) Unwrap() http.ResponseWriter {
+	return t.ResponseWriter
+}
@@ -0,0 +1,215 @@
+package mock
+
+import (
+	"fmt"
+	"path"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+)
+
+// Anonymizer replaces the personal data of captured outlets (names, phones,
+// emails, addresses, links and free text) with generated values. IDs, enums,
+// counts, dates and amounts are kept. A value seen twice is replaced the same way
+// both times and different values get different replacements, so a sales rep
+// stays one person across visits and orders and two reps never merge into one.
+type Anonymizer struct {
+	g      *generator
+	seen   map[string]string
+	used   map[string]bool            // replacements handed out, by kind and value
+	places map[[4]string]*pb.Location // by address, city, state and postal code
+}
+
+// maxAttempts bounds how often a replacement is regenerated before a counter
+// is appended to make it unique
+const maxAttempts = 10
+
+// NewAnonymizer creates an anonymizer drawing replacements from the given locale
+func NewAnonymizer(locale string) *Anonymizer {
+	return &Anonymizer{
+		g:      newGenerator(MockSettings{Locale: locale}),
+		seen:   map[string]string{},
+		used:   map[string]bool{},
+		places: map[[4]string]*pb.Location{},
+	}
+}
+
+// replace returns the replacement of value within kind, generating it on first
+// sight. Empty values stay empty so the shape of the data doesn't change.
+func (a *Anonymizer) replace(kind, value string, generate func() string) string {
+	if value == "" {
+		return ""
+	}
+	key := kind + "\x00" + value
+	if replacement, ok := a.seen[key]; ok {
+		return replacement
+	}
+	replacement := a.unique(kind, generate)
+	a.seen[key] = replacement
+	return replacement
+}
+
+// unique generates a replacement not handed out within kind yet. Once the
+// generator keeps repeating itself, e.g. because its pool is used up, a counter
+// is appended: "Jan Jansen 2".
+func (a *Anonymizer) unique(kind string, generate func() string) string {
+	replacement := generate()
+	for attempt := 1; a.used[kind+"\x00"+replacement] && attempt < maxAttempts; attempt++ {
+		replacement = generate()
+	}
+	for n, base := 2, replacement; a.used[kind+"\x00"+replacement]; n++ {
+		replacement = fmt.Sprintf("%s %d", base, n)
+	}
+	a.used[kind+"\x00"+replacement] = true
+	return replacement
+}
+
+// pick replaces value with an entry of pool, preferring entries not used within kind
+func (a *Anonymizer) pick(kind, value string, pool []string) string {
+	return a.replace(kind, value, func() string {
+		var unused []string
+		for _, entry := range pool {
+			if !a.used[kind+"\x00"+entry] {
+				unused = append(unused, entry)
+			}
+		}
+		if len(unused) == 0 {
+			return a.g.randomChoice(pool)
+		}
+		return a.g.randomChoice(unused)
+	})
+}
+
+// Response anonymizes every outlet of the response in place
+func (a *Anonymizer) Response(response *pb.OutletDetailsResponse) {
+	for _, outlet := range response.Details {
+		a.Outlet(outlet)
+	}
+}
+
+// Outlet anonymizes an outlet in place
+func (a *Anonymizer) Outlet(outlet *pb.OutletDetails) {
+	locale := a.g.locale
+
+	outlet.Name = a.pick("outlet", outlet.Name, locale.OutletNames)
+	outlet.Code = a.replace("code", outlet.Code, func() string {
+		return fmt.Sprintf("ST-%s-%03d", a.g.randomString(2), a.g.rng.Intn(999)+1)
+	})
+	outlet.Thumbnail = a.replace("thumbnail", outlet.Thumbnail, a.thumbnail)
+	a.location(outlet.Location)
+
+	for _, contact := range outlet.ContactPoints {
+		contact.Name = a.pick("contact", contact.Name, locale.StoreManagers)
+		contact.Phone = a.replace("phone", contact.Phone, func() string { return a.g.format(locale.PhoneFormat) })
+		contact.Email = a.replace("email", contact.Email, func() string {
+			return fmt.Sprintf("%s@%s", a.g.randomString(8), locale.EmailDomain)
+		})
+	}
+
+	for _, visit := range outlet.VisitHistory {
+		visit.SalesRepName = a.pick("rep", visit.SalesRepName, locale.SalesReps)
+		visit.Purpose = a.pick("purpose", visit.Purpose, locale.VisitPurposes)
+		visit.Summary = a.pick("summary", visit.Summary, locale.VisitSummaries)
+		for _, action := range visit.ActionsTaken {
+			action.Description = a.pick("action", action.Description, locale.ActionDescriptions)
+		}
+		for i, attachment := range visit.Attachments {
+			visit.Attachments[i] = a.replace("attachment", attachment, func() string {
+				return fmt.Sprintf("document_%s%s", a.g.randomString(6), path.Ext(attachment))
+			})
+		}
+	}
+
+	for _, order := range outlet.OrderHistory {
+		order.SalesRepName = a.pick("rep", order.SalesRepName, locale.SalesReps)
+		order.Notes = a.pick("orderNote", order.Notes, locale.OrderNotes)
+		if delivery := order.DeliveryInfo; delivery != nil {
+			delivery.DeliveryAddress = a.replace("street", delivery.DeliveryAddress, a.g.randomStreetAddress)
+			delivery.DeliveryNotes = a.pick("deliveryNote", delivery.DeliveryNotes, locale.DeliveryNotes)
+		}
+	}
+
+	for _, nearby := range outlet.OutletsNearby {
+		nearby.Name = a.pick("outlet", nearby.Name, locale.OutletNames)
+		nearby.Thumbnail = a.replace("thumbnail", nearby.Thumbnail, a.thumbnail)
+		a.location(nearby.Location)
+	}
+
+	for i, note := range outlet.Notes {
+		note.Title = a.pick("noteTitle", note.Title, locale.NoteTitles)
+		note.Content = a.replace("noteContent", note.Content, func() string {
+			return a.g.format(a.g.randomChoice(locale.NoteContents), "n", fmt.Sprint(i+1))
+		})
+	}
+
+	for _, asset := range outlet.AssetList {
+		asset.Name = a.pick("assetName", asset.Name, locale.AssetNames)
+		asset.LocationDetails = a.replace("assetLocation", asset.LocationDetails, a.g.randomAssetLocation)
+		for _, maintenance := range asset.MaintenanceHistory {
+			maintenance.Description = a.pick("maintenance", maintenance.Description, locale.MaintenanceDescriptions)
+			maintenance.Technician = a.pick("technician", maintenance.Technician, locale.MaintenanceTechnicians)
+		}
+	}
+
+	for i, item := range outlet.Checklist {
+		item.Title = a.pick("checklistTitle", item.Title, locale.ChecklistTitles)
+		item.Description = a.replace("checklistDescription", item.Description, func() string {
+			return a.g.format(locale.ChecklistDescriptionFmt, "n", fmt.Sprint(i+1))
+		})
+		item.Notes = a.pick("checklistNote", item.Notes, locale.ChecklistNotes)
+	}
+
+	for i, news := range outlet.News {
+		news.Title = a.pick("newsTitle", news.Title, locale.NewsTitles)
+		news.Content = a.replace("newsContent", news.Content, func() string {
+			return a.g.format(locale.NewsContentFormat, "n", fmt.Sprint(i+1))
+		})
+		news.Author = a.pick("author", news.Author, locale.NewsAuthors)
+		news.Url = a.replace("newsUrl", news.Url, func() string {
+			return a.g.format(locale.NewsURL, "n", a.g.randomString(6))
+		})
+	}
+}
+
+// location replaces the address and moves the coordinates into the locale's
+// area, keeping empty fields empty. Address, city, state and postal code are
+// replaced as one place, so they keep matching each other.
+func (a *Anonymizer) location(location *pb.Location) {
+	if location == nil {
+		return
+	}
+	key := [4]string{location.Address, location.City, location.State, location.PostalCode}
+	generated, ok := a.places[key]
+	if !ok {
+		// Different places keep different addresses, the rest may repeat
+		address := a.unique("place", func() string {
+			generated = a.g.generateRandomLocation()
+			return generated.Address
+		})
+		generated.Address = address
+		a.places[key] = generated
+	}
+
+	location.Address = keepEmpty(location.Address, generated.Address)
+	location.City = keepEmpty(location.City, generated.City)
+	location.State = keepEmpty(location.State, generated.State)
+	location.PostalCode = keepEmpty(location.PostalCode, generated.PostalCode)
+	// All places lie in the locale's country
+	location.Country = keepEmpty(location.Country, generated.Country)
+	if location.Latitude != 0 || location.Longitude != 0 {
+		location.Latitude = generated.Latitude
+		location.Longitude = generated.Longitude
+	}
+}
+
+// thumbnail returns a placeholder image URL unique to the replaced image
+func (a *Anonymizer) thumbnail() string {
+	return fmt.Sprintf("https://picsum.photos/seed/%s/100", a.g.randomString(8))
+}
+
+// keepEmpty returns replacement unless value is empty
+func keepEmpty(value, replacement string) string {
+	if value == "" {
+		return ""
+	}
+	return replacement
+}
@@ -0,0 +1,266 @@
+package mock
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const DefaultLocale = "en-US"
+
+// Locale holds the data pools used to generate text, names and addresses for one market.
+//
+// Formats use placeholders: '#' is a random digit, '?' a random uppercase letter,
+// and {name} is replaced by the value of the same name (e.g. {street}, {number}, {n}).
+type Locale struct {
+	Code        string  `json:"code"`
+	Country     string  `json:"country"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	EmailDomain string  `json:"emailDomain"`
+	NewsURL     string  `json:"newsUrl"`
+	Market      Market  `json:"market"`
+
+	StreetFormat     string `json:"streetFormat"`
+	PostalCodeFormat string `json:"postalCodeFormat"`
+	PhoneFormat      string `json:"phoneFormat"`
+
+	States  []string `json:"states"`
+	Cities  []string `json:"cities"`
+	Streets []string `json:"streets"`
+
+	OutletNames   []string `json:"outletNames"`
+	StoreManagers []string `json:"storeManagers"`
+	SalesReps     []string `json:"salesReps"`
+	ProductNames  []string `json:"productNames"`
+	ContactRoles  []string `json:"contactRoles"`
+	Relationships []string `json:"relationships"`
+
+	VisitPurposes      []string `json:"visitPurposes"`
+	VisitSummaries     []string `json:"visitSummaries"`
+	ActionDescriptions []string `json:"actionDescriptions"`
+	OrderNotes         []string `json:"orderNotes"`
+	DeliveryNotes      []string `json:"deliveryNotes"`
+
+	NoteTitles   []string `json:"noteTitles"`
+	NoteContents []string `json:"noteContents"`
+	NoteTags     []string `json:"noteTags"`
+
+	AssetNames              []string `json:"assetNames"`
+	AssetLocationFormat     string   `json:"assetLocationFormat"`
+	AssetConditions         []string `json:"assetConditions"`
+	MaintenanceDescriptions []string `json:"maintenanceDescriptions"`
+	MaintenanceTechnicians  []string `json:"maintenanceTechnicians"`
+	ChecklistTitles         []string `json:"checklistTitles"`
+	ChecklistDescriptionFmt string   `json:"checklistDescriptionFormat"`
+	ChecklistNotes          []string `json:"checklistNotes"`
+	NewsTitles              []string `json:"newsTitles"`
+	NewsContentFormat       string   `json:"newsContentFormat"`
+	NewsAuthors             []string `json:"newsAuthors"`
+	NewsTags                []string `json:"newsTags"`
+}
+
+// Market holds the pricing rules of a locale. Catalog list prices are spread
+// between ListPriceMin and ListPriceMax, other amounts (credit limits, costs) are
+// multiplied by AmountScale relative to their USD baseline.
+type Market struct {
+	Currency          string  `json:"currency"`
+	VATRate           float64 `json:"vatRate"`
+	MinorUnits        int     `json:"minorUnits"`
+	RoundingIncrement float64 `json:"roundingIncrement,omitempty"`
+	ListPriceMin      float64 `json:"listPriceMin"`
+	ListPriceMax      float64 `json:"listPriceMax"`
+	AmountScale       float64 `json:"amountScale"`
+}
+
+// Round rounds amount to the currency's minor unit, or to RoundingIncrement
+// for markets that don't use their smallest coin (e.g. 0.05)
+func (m Market) Round(amount float64) float64 {
+	if m.RoundingIncrement > math.Pow10(-m.MinorUnits) {
+		amount = math.Round(amount/m.RoundingIncrement) * m.RoundingIncrement
+	}
+	// Dividing by the power of ten gives the closest float to the decimal
+	// amount, multiplying by 0.01 would leave artifacts like 12.340000000000002
+	scale := math.Pow10(m.MinorUnits)
+	return math.Round(amount*scale) / scale
+}
+
+// Scale converts a USD baseline amount into the market's currency
+func (m Market) Scale(amount float64) float64 {
+	if m.AmountScale == 0 {
+		return m.Round(amount)
+	}
+	return m.Round(amount * m.AmountScale)
+}
+
+//go:embed locales/*.json
+var embeddedLocales embed.FS
+
+// locales are loaded once at startup and only read afterwards
+var (
+	locales           = map[string]*Locale{}
+	defaultLocaleData []byte
+)
+
+func init() {
+	if err := loadLocales(embeddedLocales, "locales"); err != nil {
+		panic(err)
+	}
+}
+
+// LoadLocales adds or replaces locales with the *.json files in dir.
+// Missing pools in a file fall back to the default locale.
+// It must be called before any outlets are generated.
+func LoadLocales(dir string) error {
+	return loadLocales(os.DirFS(dir), ".")
+}
+
+func loadLocales(fsys fs.FS, dir string) error {
+	paths, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	// The default locale has to be loaded first so others can fall back to it
+	sort.Slice(paths, func(i, j int) bool {
+		return strings.HasSuffix(paths[i], DefaultLocale+".json") && !strings.HasSuffix(paths[j], DefaultLocale+".json")
+	})
+
+	for _, file := range paths {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		// Decoding the default data first gives each locale its own copy of the
+		// fallback pools; copying the struct would share the slices' backing arrays
+		locale := &Locale{}
+		if defaultLocaleData != nil {
+			if err := json.Unmarshal(defaultLocaleData, locale); err != nil {
+				return err
+			}
+			// The code is not a pool, files without one are named after the file
+			locale.Code = ""
+		}
+		if err := json.Unmarshal(data, locale); err != nil {
+			return fmt.Errorf("locale %s: %w", file, err)
+		}
+		if locale.Code == "" {
+			locale.Code = strings.TrimSuffix(path.Base(file), ".json")
+		}
+		if err := locale.validate(); err != nil {
+			return fmt.Errorf("locale %s: %w", file, err)
+		}
+		locales[locale.Code] = locale
+		if locale.Code == DefaultLocale {
+			// Keep the merged locale, a partial file must not take pools away from the others
+			if defaultLocaleData, err = json.Marshal(locale); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validate rejects locales with an empty pool, the generators pick from every
+// pool and can't fall back once the default locale has been merged in
+func (l *Locale) validate() error {
+	value := reflect.ValueOf(l).Elem()
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		if field.Type.Kind() == reflect.Slice && value.Field(i).Len() == 0 {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			return fmt.Errorf("%s must not be empty", name)
+		}
+	}
+	return nil
+}
+
+// Locales returns the codes of all available locales
+func Locales() []string {
+	codes := make([]string, 0, len(locales))
+	for code := range locales {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// MatchLocale picks the best available locale for an Accept-Language header,
+// falling back to DefaultLocale. Both exact tags (nl-NL) and bare languages (nl)
+// are matched; quality values are honoured.
+func MatchLocale(acceptLanguage string) string {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			fmt.Sscanf(q, "%g", &quality)
+		}
+		candidates = append(candidates, candidate{tag: tag, quality: quality})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+
+	for _, c := range candidates {
+		if code, ok := findLocale(c.tag); ok {
+			return code
+		}
+	}
+	return DefaultLocale
+}
+
+func findLocale(tag string) (string, bool) {
+	for code := range locales {
+		if strings.EqualFold(code, tag) {
+			return code, true
+		}
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	for _, code := range Locales() {
+		if codeLanguage, _, _ := strings.Cut(code, "-"); strings.EqualFold(codeLanguage, language) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+func getLocale(code string) *Locale {
+	if code != "" {
+		if found, ok := findLocale(code); ok {
+			return locales[found]
+		}
+	}
+	return locales[DefaultLocale]
+}
+
+// format expands a locale format string, see Locale. Random digits and letters
+// are filled in before the named values so values may contain '#' and '?'.
+func (g *generator) format(pattern string, values ...string) string {
+	result := []byte(pattern)
+	for i, c := range result {
+		switch c {
+		case '#':
+			result[i] = byte('0' + g.rng.Intn(10))
+		case '?':
+			result[i] = byte('A' + g.rng.Intn(26))
+		}
+	}
+
+	pattern = string(result)
+	for i := 0; i+1 < len(values); i += 2 {
+		pattern = strings.ReplaceAll(pattern, "{"+values[i]+"}", values[i+1])
+	}
+	return pattern
+}
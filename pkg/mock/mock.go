@@ -2,7 +2,9 @@ package mock
 
 import (
 	"fmt"
+	"hash/fnv"
 	"math/rand"
+	"sort"
 	"time"
 
 	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
@@ -11,61 +13,46 @@ import (
 )
 
 type MockSettings struct {
-	AverageNotesList               int `json:"averageNotesList,omitempty"`
-	AverageVisitHistory            int `json:"averageVisitHistory,omitempty"`
-	AverageNumberOfOrders          int `json:"averageNumberOfOrders,omitempty"`
-	AverageOrderItemsPerOrder      int `json:"averageOrderItemsPerOrder,omitempty"`
-	AverageTopProductsInStatistics int `json:"averageTopProductsInStatistics,omitempty"`
-	AverageOutletsNearby           int `json:"averageOutletsNearby,omitempty"`
-	AverageAssetList               int `json:"averageAssetList,omitempty"`
-	AverageChecklist               int `json:"averageChecklist,omitempty"`
-	AverageNews                    int `json:"averageNews,omitempty"`
+	AverageNotesList               int    `json:"averageNotesList,omitempty"`
+	AverageVisitHistory            int    `json:"averageVisitHistory,omitempty"`
+	AverageNumberOfOrders          int    `json:"averageNumberOfOrders,omitempty"`
+	AverageOrderItemsPerOrder      int    `json:"averageOrderItemsPerOrder,omitempty"`
+	AverageTopProductsInStatistics int    `json:"averageTopProductsInStatistics,omitempty"`
+	AverageOutletsNearby           int    `json:"averageOutletsNearby,omitempty"`
+	AverageAssetList               int    `json:"averageAssetList,omitempty"`
+	AverageChecklist               int    `json:"averageChecklist,omitempty"`
+	AverageNews                    int    `json:"averageNews,omitempty"`
+	Locale                         string `json:"locale,omitempty"`
+
+	// Products replace the locale's product names for orders, visits and
+	// statistics when set
+	Products []Product `json:"-"`
 }
 
 type MockSettingsOptional struct {
-	AverageNotesList               *int `json:"averageNotesList,omitempty"`
-	AverageVisitHistory            *int `json:"averageVisitHistory,omitempty"`
-	AverageNumberOfOrders          *int `json:"averageNumberOfOrders,omitempty"`
-	AverageOrderItemsPerOrder      *int `json:"averageOrderItemsPerOrder,omitempty"`
-	AverageTopProductsInStatistics *int `json:"averageTopProductsInStatistics,omitempty"`
-	AverageOutletsNearby           *int `json:"averageOutletsNearby,omitempty"`
-	AverageAssetList               *int `json:"averageAssetList,omitempty"`
-	AverageChecklist               *int `json:"averageChecklist,omitempty"`
-	AverageNews                    *int `json:"averageNews,omitempty"`
-}
-
-// Sample data pools for randomization
+	AverageNotesList               *int    `json:"averageNotesList,omitempty"`
+	AverageVisitHistory            *int    `json:"averageVisitHistory,omitempty"`
+	AverageNumberOfOrders          *int    `json:"averageNumberOfOrders,omitempty"`
+	AverageOrderItemsPerOrder      *int    `json:"averageOrderItemsPerOrder,omitempty"`
+	AverageTopProductsInStatistics *int    `json:"averageTopProductsInStatistics,omitempty"`
+	AverageOutletsNearby           *int    `json:"averageOutletsNearby,omitempty"`
+	AverageAssetList               *int    `json:"averageAssetList,omitempty"`
+	AverageChecklist               *int    `json:"averageChecklist,omitempty"`
+	AverageNews                    *int    `json:"averageNews,omitempty"`
+	Locale                         *string `json:"locale,omitempty"`
+}
+
+// Product is a catalog entry referenced by order items and statistics.
+// A zero ListPrice is derived from the market's price range.
+type Product struct {
+	ProductID string
+	Name      string
+	SKU       string
+	ListPrice float64
+}
+
+// Enum pools for randomization, text pools live in the locale files
 var (
-	outletNames = []string{
-		"SuperMart Downtown", "MegaStore Central", "QuickMart Express", "FamilyShop Plus",
-		"GroceryWorld", "FreshMart Deluxe", "CityStore Premium", "NeighborhoodMart",
-		"ValueMart Pro", "LocalShop Central", "MarketPlace Elite", "ShopRite Corner",
-	}
-
-	storeManagers = []string{
-		"John Smith", "Sarah Johnson", "Mike Wilson", "Emily Davis",
-		"Robert Brown", "Lisa Anderson", "David Miller", "Jennifer Garcia",
-		"Michael Taylor", "Amanda Wilson", "Christopher Lee", "Jessica Martinez",
-	}
-
-	salesReps = []string{
-		"Mike Wilson", "Sarah Thompson", "Alex Rodriguez", "Maria Garcia",
-		"James Brown", "Nicole Taylor", "Kevin Davis", "Rachel Martinez",
-		"Daniel Lee", "Amanda Clark", "Steven White", "Michelle Lopez",
-	}
-
-	productNames = []string{
-		"Premium Cola 24-pack", "Organic Chips Variety Pack", "Energy Drink Mix",
-		"Sparkling Water Cases", "Protein Bars Box", "Healthy Snack Mix",
-		"Juice Bottle Set", "Coffee Bean Bags", "Tea Collection Box",
-		"Vitamin Water Pack", "Sports Drink Cases", "Smoothie Mix Packets",
-	}
-
-	cities = []string{
-		"Metro City", "Downtown Plaza", "Central District", "Uptown Area",
-		"Riverside", "Hillside", "Lakeside", "Parkview", "Westside", "Eastgate",
-	}
-
 	noteTypes = []pb.NoteType{
 		pb.NoteType_NOTE_TYPE_GENERAL,
 		pb.NoteType_NOTE_TYPE_SALES,
@@ -94,100 +81,231 @@ var (
 	}
 )
 
+// generator carries the per-request state shared by the generation functions.
+// All randomness comes from rng and all dates are relative to now, so a seeded
+// generator always produces the same data.
+type generator struct {
+	locale   *Locale
+	market   Market
+	products []Product
+	rng      *rand.Rand
+	now      time.Time
+}
+
+func newGenerator(settings MockSettings) *generator {
+	return newSeededGenerator(settings, rand.Int63(), time.Now())
+}
+
+func newSeededGenerator(settings MockSettings, seed int64, now time.Time) *generator {
+	g := &generator{
+		locale: getLocale(settings.Locale),
+		rng:    rand.New(rand.NewSource(seed)),
+		now:    now,
+	}
+	g.market = g.locale.Market
+
+	if len(settings.Products) > 0 {
+		g.products = append([]Product(nil), settings.Products...)
+	} else {
+		// Product IDs and SKUs are shared across locales, only the names are localized
+		g.products = make([]Product, len(g.locale.ProductNames))
+		for i, name := range g.locale.ProductNames {
+			g.products[i] = Product{
+				ProductID: fmt.Sprintf("prod-%03d", i+1),
+				Name:      name,
+				SKU:       fmt.Sprintf("SKU-%03d", i+1),
+			}
+		}
+	}
+	for i := range g.products {
+		if g.products[i].ListPrice == 0 {
+			g.products[i].ListPrice = g.listPrice(g.products[i].ProductID)
+		}
+	}
+	return g
+}
+
+// listPrice places a product at a stable point of the market's price range,
+// so the same product always costs the same within a market
+func (g *generator) listPrice(productID string) float64 {
+	hash := fnv.New32a()
+	hash.Write([]byte(productID))
+	position := float64(hash.Sum32()%1000) / 1000
+	return g.market.Round(g.market.ListPriceMin + position*(g.market.ListPriceMax-g.market.ListPriceMin))
+}
+
+func (g *generator) randomProduct() Product {
+	return g.products[g.rng.Intn(len(g.products))]
+}
+
+// GenerateMockedOutlet builds a fully random outlet
 func GenerateMockedOutlet(outletID string, settings MockSettings) *pb.OutletDetails {
-	rand.Seed(time.Now().UnixNano())
-	now := timestamppb.Now()
+	return CompleteOutlet(&pb.OutletDetails{OutletId: outletID}, settings)
+}
+
+// CompleteOutlet fills every unset field and empty sub-collection of outlet with
+// generated data according to settings. Anything already present is kept as is,
+// which lets fixtures describe only the parts of an outlet they care about.
+func CompleteOutlet(outlet *pb.OutletDetails, settings MockSettings) *pb.OutletDetails {
+	return newGenerator(settings).completeOutlet(outlet, settings)
+}
 
-	outlet := &pb.OutletDetails{
-		OutletId:  outletID,
-		Name:      randomChoice(outletNames),
-		Code:      fmt.Sprintf("ST-%s-%03d", randomString(2), rand.Intn(999)+1),
-		Thumbnail: "https://picsum.photos/100",
-		Type:      randomOutletType(),
-		Status:    pb.OutletStatus_OUTLET_STATUS_ACTIVE,
-		Location:  generateRandomLocation(),
-		CreatedAt: timestamppb.New(time.Now().AddDate(-rand.Intn(3)-1, -rand.Intn(12), -rand.Intn(30))),
-		UpdatedAt: now,
+// CompleteSeededOutlet is CompleteOutlet drawing from seed, with dates relative
+// to now, so the same arguments always complete an outlet the same way
+func CompleteSeededOutlet(outlet *pb.OutletDetails, settings MockSettings, seed int64, now time.Time) *pb.OutletDetails {
+	return newSeededGenerator(settings, seed, now).completeOutlet(outlet, settings)
+}
+
+// GenerateDataset builds count outlets (outlet-001, outlet-002, ...) from a
+// single random source, so the same seed, settings and reference time always
+// produce the same dataset
+func GenerateDataset(count int, settings MockSettings, seed int64, now time.Time) []*pb.OutletDetails {
+	g := newSeededGenerator(settings, seed, now)
+	outlets := make([]*pb.OutletDetails, count)
+	for i := range outlets {
+		outlets[i] = g.completeOutlet(&pb.OutletDetails{OutletId: fmt.Sprintf("outlet-%03d", i+1)}, settings)
+	}
+	return outlets
+}
+
+// GenerateNote builds a single note with the given ID, e.g. to add to a stored outlet
+func GenerateNote(noteID string, settings MockSettings) *pb.Note {
+	note := newGenerator(settings).generateNotes(1)[0]
+	note.NoteId = noteID
+	note.CreatedAt = timestamppb.Now()
+	note.UpdatedAt = note.CreatedAt
+	return note
+}
+
+// GenerateVisit builds a single visit with the given ID
+func GenerateVisit(visitID string, settings MockSettings) *pb.Visit {
+	visit := newGenerator(settings).generateVisitHistory(1)[0]
+	visit.VisitId = visitID
+	return visit
+}
+
+// GenerateOrder builds a single order with the given ID
+func GenerateOrder(orderID string, settings MockSettings) *pb.Order {
+	order := newGenerator(settings).generateOrderHistory(1, settings.AverageOrderItemsPerOrder)[0]
+	order.OrderId = orderID
+	return order
+}
+
+// GenerateChecklistItem builds a single checklist item with the given ID
+func GenerateChecklistItem(itemID string, settings MockSettings) *pb.ChecklistItem {
+	item := newGenerator(settings).generateChecklist(1)[0]
+	item.ItemId = itemID
+	return item
+}
+
+func (g *generator) completeOutlet(outlet *pb.OutletDetails, settings MockSettings) *pb.OutletDetails {
+	now := timestamppb.New(g.now)
+
+	if outlet.Name == "" {
+		outlet.Name = g.randomChoice(g.locale.OutletNames)
+	}
+	if outlet.Code == "" {
+		outlet.Code = fmt.Sprintf("ST-%s-%03d", g.randomString(2), g.rng.Intn(999)+1)
+	}
+	if outlet.Thumbnail == "" {
+		outlet.Thumbnail = "https://picsum.photos/100"
+	}
+	if outlet.Type == pb.OutletType_OUTLET_TYPE_UNSPECIFIED {
+		outlet.Type = g.randomOutletType()
+	}
+	if outlet.Status == pb.OutletStatus_OUTLET_STATUS_UNSPECIFIED {
+		outlet.Status = pb.OutletStatus_OUTLET_STATUS_ACTIVE
+	}
+	outlet.Location = g.completeLocation(outlet.Location)
+	if outlet.CreatedAt == nil {
+		outlet.CreatedAt = timestamppb.New(g.now.AddDate(-g.rng.Intn(3)-1, -g.rng.Intn(12), -g.rng.Intn(30)))
+	}
+	if outlet.UpdatedAt == nil {
+		outlet.UpdatedAt = now
 	}
 
 	// Generate contact points (always 1-3)
-	outlet.ContactPoints = generateContactPoints(rand.Intn(3) + 1)
+	if len(outlet.ContactPoints) == 0 {
+		outlet.ContactPoints = g.generateContactPoints(g.rng.Intn(3) + 1)
+	}
 
 	// Generate visit history
-	if settings.AverageVisitHistory > 0 {
-		visitCount := randomizeCount(settings.AverageVisitHistory)
-		outlet.VisitHistory = generateVisitHistory(visitCount)
+	if len(outlet.VisitHistory) == 0 && settings.AverageVisitHistory > 0 {
+		visitCount := g.randomizeCount(settings.AverageVisitHistory)
+		outlet.VisitHistory = g.generateVisitHistory(visitCount)
 	}
 
 	// Generate order history
-	if settings.AverageNumberOfOrders > 0 {
-		orderCount := randomizeCount(settings.AverageNumberOfOrders)
-		outlet.OrderHistory = generateOrderHistory(orderCount, settings.AverageOrderItemsPerOrder)
+	if len(outlet.OrderHistory) == 0 && settings.AverageNumberOfOrders > 0 {
+		orderCount := g.randomizeCount(settings.AverageNumberOfOrders)
+		outlet.OrderHistory = g.generateOrderHistory(orderCount, settings.AverageOrderItemsPerOrder)
 	}
 
 	// Generate statistics
-	outlet.Statistics = generateStatistics(settings.AverageTopProductsInStatistics, outlet.OrderHistory, outlet.VisitHistory)
+	if outlet.Statistics == nil {
+		outlet.Statistics = g.generateStatistics(settings.AverageTopProductsInStatistics, outlet.OrderHistory, outlet.VisitHistory)
+	}
 
 	// Generate nearby outlets
-	if settings.AverageOutletsNearby > 0 {
-		nearbyCount := randomizeCount(settings.AverageOutletsNearby)
-		outlet.OutletsNearby = generateNearbyOutlets(nearbyCount, outlet.Location)
+	if len(outlet.OutletsNearby) == 0 && settings.AverageOutletsNearby > 0 {
+		nearbyCount := g.randomizeCount(settings.AverageOutletsNearby)
+		outlet.OutletsNearby = g.generateNearbyOutlets(nearbyCount, outlet.Location)
 	}
 
 	// Generate notes
-	if settings.AverageNotesList > 0 {
-		notesCount := randomizeCount(settings.AverageNotesList)
-		outlet.Notes = generateNotes(notesCount)
+	if len(outlet.Notes) == 0 && settings.AverageNotesList > 0 {
+		notesCount := g.randomizeCount(settings.AverageNotesList)
+		outlet.Notes = g.generateNotes(notesCount)
 	}
 
 	// Generate assets
-	if settings.AverageAssetList > 0 {
-		assetCount := randomizeCount(settings.AverageAssetList)
-		outlet.AssetList = generateAssets(assetCount)
+	if len(outlet.AssetList) == 0 && settings.AverageAssetList > 0 {
+		assetCount := g.randomizeCount(settings.AverageAssetList)
+		outlet.AssetList = g.generateAssets(assetCount)
 	}
 
 	// Generate checklist
-	if settings.AverageChecklist > 0 {
-		checklistCount := randomizeCount(settings.AverageChecklist)
-		outlet.Checklist = generateChecklist(checklistCount)
+	if len(outlet.Checklist) == 0 && settings.AverageChecklist > 0 {
+		checklistCount := g.randomizeCount(settings.AverageChecklist)
+		outlet.Checklist = g.generateChecklist(checklistCount)
 	}
 
 	// Generate news
-	if settings.AverageNews > 0 {
-		newsCount := randomizeCount(settings.AverageNews)
-		outlet.News = generateNews(newsCount)
+	if len(outlet.News) == 0 && settings.AverageNews > 0 {
+		newsCount := g.randomizeCount(settings.AverageNews)
+		outlet.News = g.generateNews(newsCount)
 	}
 
 	return outlet
 }
 
-func randomizeCount(average int) int {
+func (g *generator) randomizeCount(average int) int {
 	if average <= 1 {
 		return average
 	}
 	// Generate count with ±50% variance from average
 	min := average / 2
 	max := average + (average / 2)
-	return rand.Intn(max-min+1) + min
+	return g.rng.Intn(max-min+1) + min
 }
 
-func randomChoice(slice []string) string {
+func (g *generator) randomChoice(slice []string) string {
 	if len(slice) == 0 {
 		return ""
 	}
-	return slice[rand.Intn(len(slice))]
+	return slice[g.rng.Intn(len(slice))]
 }
 
-func randomString(length int) string {
+func (g *generator) randomString(length int) string {
 	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	result := make([]byte, length)
 	for i := range result {
-		result[i] = charset[rand.Intn(len(charset))]
+		result[i] = charset[g.rng.Intn(len(charset))]
 	}
 	return string(result)
 }
 
-func randomOutletType() pb.OutletType {
+func (g *generator) randomOutletType() pb.OutletType {
 	types := []pb.OutletType{
 		pb.OutletType_OUTLET_TYPE_RETAIL,
 		pb.OutletType_OUTLET_TYPE_WHOLESALE,
@@ -196,63 +314,95 @@ func randomOutletType() pb.OutletType {
 		pb.OutletType_OUTLET_TYPE_CONVENIENCE_STORE,
 		pb.OutletType_OUTLET_TYPE_RESTAURANT,
 	}
-	return types[rand.Intn(len(types))]
+	return types[g.rng.Intn(len(types))]
 }
 
-func generateRandomLocation() *pb.Location {
-	city := randomChoice(cities)
+func (g *generator) generateRandomLocation() *pb.Location {
+	city := g.randomChoice(g.locale.Cities)
 	return &pb.Location{
-		Address:    fmt.Sprintf("%d %s Street, %s", rand.Intn(999)+1, randomChoice([]string{"Main", "Oak", "Pine", "Elm", "First", "Second"}), city),
+		Address:    fmt.Sprintf("%s, %s", g.randomStreetAddress(), city),
 		City:       city,
-		State:      "Central State",
-		PostalCode: fmt.Sprintf("%05d", rand.Intn(99999)),
-		Country:    "Country Name",
-		Latitude:   40.7128 + (rand.Float64()-0.5)*0.1,
-		Longitude:  -74.0060 + (rand.Float64()-0.5)*0.1,
+		State:      g.randomChoice(g.locale.States),
+		PostalCode: g.format(g.locale.PostalCodeFormat),
+		Country:    g.locale.Country,
+		Latitude:   g.locale.Latitude + (g.rng.Float64()-0.5)*0.1,
+		Longitude:  g.locale.Longitude + (g.rng.Float64()-0.5)*0.1,
+	}
+}
+
+func (g *generator) randomStreetAddress() string {
+	return g.format(g.locale.StreetFormat, "street", g.randomChoice(g.locale.Streets), "number", fmt.Sprint(g.rng.Intn(999)+1))
+}
+
+// completeLocation fills the empty fields of a partially known location
+func (g *generator) completeLocation(location *pb.Location) *pb.Location {
+	generated := g.generateRandomLocation()
+	if location == nil {
+		return generated
+	}
+	if location.City == "" {
+		location.City = generated.City
 	}
+	if location.Address == "" {
+		location.Address = generated.Address
+	}
+	if location.State == "" {
+		location.State = generated.State
+	}
+	if location.PostalCode == "" {
+		location.PostalCode = generated.PostalCode
+	}
+	if location.Country == "" {
+		location.Country = generated.Country
+	}
+	if location.Latitude == 0 && location.Longitude == 0 {
+		location.Latitude = generated.Latitude
+		location.Longitude = generated.Longitude
+	}
+	return location
 }
 
-func generateContactPoints(count int) []*pb.ContactPoint {
+func (g *generator) generateContactPoints(count int) []*pb.ContactPoint {
 	contacts := make([]*pb.ContactPoint, count)
 	for i := 0; i < count; i++ {
-		name := randomChoice(storeManagers)
+		name := g.randomChoice(g.locale.StoreManagers)
 		contacts[i] = &pb.ContactPoint{
 			ContactId: fmt.Sprintf("contact-%03d", i+1),
 			Name:      name,
-			Role:      randomChoice([]string{"Store Manager", "Assistant Manager", "Buyer", "Operations Manager"}),
-			Phone:     fmt.Sprintf("+1-555-%04d", rand.Intn(9999)),
-			Email:     fmt.Sprintf("%s@store.com", randomString(8)),
+			Role:      g.randomChoice(g.locale.ContactRoles),
+			Phone:     g.format(g.locale.PhoneFormat),
+			Email:     fmt.Sprintf("%s@%s", g.randomString(8), g.locale.EmailDomain),
 			Type:      pb.ContactType_CONTACT_TYPE_MANAGER,
 			IsPrimary: i == 0, // First contact is primary
-			CreatedAt: timestamppb.New(time.Now().AddDate(0, -rand.Intn(12), -rand.Intn(30))),
+			CreatedAt: timestamppb.New(g.now.AddDate(0, -g.rng.Intn(12), -g.rng.Intn(30))),
 		}
 	}
 	return contacts
 }
 
-func generateVisitHistory(count int) []*pb.Visit {
+func (g *generator) generateVisitHistory(count int) []*pb.Visit {
 	visits := make([]*pb.Visit, count)
 	for i := 0; i < count; i++ {
-		visitDate := timestamppb.New(time.Now().AddDate(0, 0, -(rand.Intn(365))))
+		visitDate := timestamppb.New(g.now.AddDate(0, 0, -(g.rng.Intn(365))))
 		visits[i] = &pb.Visit{
 			VisitId:           fmt.Sprintf("visit-%03d", i+1),
-			SalesRepId:        fmt.Sprintf("rep-%03d", rand.Intn(10)+1),
-			SalesRepName:      randomChoice(salesReps),
+			SalesRepId:        fmt.Sprintf("rep-%03d", g.rng.Intn(10)+1),
+			SalesRepName:      g.randomChoice(g.locale.SalesReps),
 			VisitDate:         visitDate,
-			VisitType:         randomVisitType(),
+			VisitType:         g.randomVisitType(),
 			VisitStatus:       pb.VisitStatus_VISIT_STATUS_COMPLETED,
-			Purpose:           randomChoice([]string{"Product presentation", "Order discussion", "Customer support", "Inventory check", "Relationship building"}),
-			Summary:           fmt.Sprintf("Visit completed successfully. %s", randomChoice([]string{"Client showed interest in new products.", "Discussed upcoming promotions.", "Resolved customer concerns.", "Planned next steps."})),
-			ProductsDiscussed: []string{randomChoice(productNames), randomChoice(productNames)},
-			ActionsTaken:      generateVisitActions(rand.Intn(2) + 1),
+			Purpose:           g.randomChoice(g.locale.VisitPurposes),
+			Summary:           g.randomChoice(g.locale.VisitSummaries),
+			ProductsDiscussed: []string{g.randomProduct().Name, g.randomProduct().Name},
+			ActionsTaken:      g.generateVisitActions(g.rng.Intn(2) + 1),
 			Attachments:       []string{fmt.Sprintf("document_%d.pdf", i+1)},
-			DurationSeconds:   int32(rand.Intn(3600) + 1800), // 30 minutes to 2 hours
+			DurationSeconds:   int32(g.rng.Intn(3600) + 1800), // 30 minutes to 2 hours
 		}
 	}
 	return visits
 }
 
-func randomVisitType() pb.VisitType {
+func (g *generator) randomVisitType() pb.VisitType {
 	types := []pb.VisitType{
 		pb.VisitType_VISIT_TYPE_SALES_CALL,
 		pb.VisitType_VISIT_TYPE_DELIVERY,
@@ -260,24 +410,24 @@ func randomVisitType() pb.VisitType {
 		pb.VisitType_VISIT_TYPE_AUDIT,
 		pb.VisitType_VISIT_TYPE_TRAINING,
 	}
-	return types[rand.Intn(len(types))]
+	return types[g.rng.Intn(len(types))]
 }
 
-func generateVisitActions(count int) []*pb.VisitAction {
+func (g *generator) generateVisitActions(count int) []*pb.VisitAction {
 	actions := make([]*pb.VisitAction, count)
 	for i := 0; i < count; i++ {
 		actions[i] = &pb.VisitAction{
 			ActionId:    fmt.Sprintf("action-%03d", i+1),
-			Description: randomChoice([]string{"Follow up on pricing", "Schedule product demo", "Negotiate terms", "Arrange delivery", "Collect payment"}),
-			Type:        randomActionType(),
-			Status:      randomActionStatus(),
-			DueDate:     timestamppb.New(time.Now().AddDate(0, 0, rand.Intn(30)+1)),
+			Description: g.randomChoice(g.locale.ActionDescriptions),
+			Type:        g.randomActionType(),
+			Status:      g.randomActionStatus(),
+			DueDate:     timestamppb.New(g.now.AddDate(0, 0, g.rng.Intn(30)+1)),
 		}
 	}
 	return actions
 }
 
-func randomActionType() pb.ActionType {
+func (g *generator) randomActionType() pb.ActionType {
 	types := []pb.ActionType{
 		pb.ActionType_ACTION_TYPE_FOLLOW_UP,
 		pb.ActionType_ACTION_TYPE_PRODUCT_DEMO,
@@ -285,316 +435,364 @@ func randomActionType() pb.ActionType {
 		pb.ActionType_ACTION_TYPE_DELIVERY_SCHEDULE,
 		pb.ActionType_ACTION_TYPE_PAYMENT_COLLECTION,
 	}
-	return types[rand.Intn(len(types))]
+	return types[g.rng.Intn(len(types))]
 }
 
-func randomActionStatus() pb.ActionStatus {
+func (g *generator) randomActionStatus() pb.ActionStatus {
 	statuses := []pb.ActionStatus{
 		pb.ActionStatus_ACTION_STATUS_PENDING,
 		pb.ActionStatus_ACTION_STATUS_IN_PROGRESS,
 		pb.ActionStatus_ACTION_STATUS_COMPLETED,
 	}
-	return statuses[rand.Intn(len(statuses))]
+	return statuses[g.rng.Intn(len(statuses))]
 }
 
-func generateOrderHistory(count int, avgItemsPerOrder int) []*pb.Order {
+func (g *generator) generateOrderHistory(count int, avgItemsPerOrder int) []*pb.Order {
 	orders := make([]*pb.Order, count)
 	for i := 0; i < count; i++ {
-		orderDate := timestamppb.New(time.Now().AddDate(0, 0, -(rand.Intn(365))))
-		itemCount := randomizeCount(avgItemsPerOrder)
+		orderDate := timestamppb.New(g.now.AddDate(0, 0, -(g.rng.Intn(365))))
+		itemCount := g.randomizeCount(avgItemsPerOrder)
 		if itemCount == 0 {
 			itemCount = 1
 		}
 
-		items := generateOrderItems(itemCount)
-		totalAmount := calculateOrderTotal(items)
+		items := g.generateOrderItems(itemCount)
+		subtotal, vat := calculateOrderTotals(items)
+		subtotal, vat = g.market.Round(subtotal), g.market.Round(vat)
+		totalAmount := g.market.Round(subtotal + vat)
 
 		orders[i] = &pb.Order{
-			OrderId:      fmt.Sprintf("order-%03d", i+1),
-			OrderNumber:  fmt.Sprintf("ORD-2024-%06d", rand.Intn(999999)+1),
-			OrderDate:    orderDate,
-			Status:       randomOrderStatus(),
-			TotalAmount:  totalAmount,
-			Currency:     "USD",
-			Items:        items,
-			PaymentInfo:  generatePaymentInfo(totalAmount, orderDate),
-			DeliveryInfo: generateDeliveryInfo(orderDate),
-			SalesRepId:   fmt.Sprintf("rep-%03d", rand.Intn(10)+1),
-			SalesRepName: randomChoice(salesReps),
-			DeliveryDate: timestamppb.New(orderDate.AsTime().AddDate(0, 0, rand.Intn(7)+1)),
-			Notes:        randomChoice([]string{"Standard delivery", "Express shipping", "Customer pickup", "Special instructions followed"}),
+			OrderId:        fmt.Sprintf("order-%03d", i+1),
+			OrderNumber:    fmt.Sprintf("ORD-2024-%06d", g.rng.Intn(999999)+1),
+			OrderDate:      orderDate,
+			Status:         g.randomOrderStatus(),
+			TotalAmount:    totalAmount,
+			SubtotalAmount: subtotal,
+			VatAmount:      vat,
+			Currency:       g.market.Currency,
+			Items:          items,
+			PaymentInfo:    g.generatePaymentInfo(totalAmount, orderDate),
+			DeliveryInfo:   g.generateDeliveryInfo(orderDate),
+			SalesRepId:     fmt.Sprintf("rep-%03d", g.rng.Intn(10)+1),
+			SalesRepName:   g.randomChoice(g.locale.SalesReps),
+			DeliveryDate:   timestamppb.New(orderDate.AsTime().AddDate(0, 0, g.rng.Intn(7)+1)),
+			Notes:          g.randomChoice(g.locale.OrderNotes),
 		}
 	}
 	return orders
 }
 
-func generateOrderItems(count int) []*pb.OrderItem {
+func (g *generator) generateOrderItems(count int) []*pb.OrderItem {
 	items := make([]*pb.OrderItem, count)
 	for i := 0; i < count; i++ {
-		unitPrice := float64(rand.Intn(50)+5) + rand.Float64()
-		quantity := int32(rand.Intn(100) + 1)
-		discountPct := float64(rand.Intn(20))
-		totalPrice := float64(quantity) * unitPrice
-		discountAmount := totalPrice * (discountPct / 100)
+		product := g.randomProduct()
+		unitPrice := product.ListPrice
+		quantity := int32(g.rng.Intn(100) + 1)
+		discountPct := float64(g.rng.Intn(20))
+		totalPrice := g.market.Round(float64(quantity) * unitPrice)
+		discountAmount := g.market.Round(totalPrice * (discountPct / 100))
+		netPrice := g.market.Round(totalPrice - discountAmount)
 
 		items[i] = &pb.OrderItem{
-			ProductId:          fmt.Sprintf("prod-%03d", rand.Intn(100)+1),
-			ProductName:        randomChoice(productNames),
-			Sku:                fmt.Sprintf("%s-%03d", randomString(3), rand.Intn(999)+1),
+			ProductId:          product.ProductID,
+			ProductName:        product.Name,
+			Sku:                product.SKU,
 			Quantity:           quantity,
 			UnitPrice:          unitPrice,
-			TotalPrice:         totalPrice - discountAmount,
+			TotalPrice:         netPrice,
 			DiscountPercentage: discountPct,
 			DiscountAmount:     discountAmount,
+			VatRate:            g.market.VATRate,
+			VatAmount:          g.market.Round(netPrice * (g.market.VATRate / 100)),
 		}
 	}
 	return items
 }
 
-func calculateOrderTotal(items []*pb.OrderItem) float64 {
-	total := 0.0
+// calculateOrderTotals sums the net line totals and their VAT
+func calculateOrderTotals(items []*pb.OrderItem) (subtotal, vat float64) {
 	for _, item := range items {
-		total += item.TotalPrice
+		subtotal += item.TotalPrice
+		vat += item.VatAmount
 	}
-	return total
+	return subtotal, vat
 }
 
-func randomOrderStatus() pb.OrderStatus {
+func (g *generator) randomOrderStatus() pb.OrderStatus {
 	statuses := []pb.OrderStatus{
 		pb.OrderStatus_ORDER_STATUS_DELIVERED,
 		pb.OrderStatus_ORDER_STATUS_SHIPPED,
 		pb.OrderStatus_ORDER_STATUS_PROCESSING,
 		pb.OrderStatus_ORDER_STATUS_CONFIRMED,
 	}
-	return statuses[rand.Intn(len(statuses))]
+	return statuses[g.rng.Intn(len(statuses))]
 }
 
-func generatePaymentInfo(amount float64, orderDate *timestamppb.Timestamp) *pb.PaymentInfo {
+func (g *generator) generatePaymentInfo(amount float64, orderDate *timestamppb.Timestamp) *pb.PaymentInfo {
 	return &pb.PaymentInfo{
-		Method:          randomPaymentMethod(),
+		Method:          g.randomPaymentMethod(),
 		Status:          pb.PaymentStatus_PAYMENT_STATUS_PAID,
 		PaymentDate:     orderDate,
 		AmountPaid:      amount,
 		AmountDue:       0.0,
-		ReferenceNumber: fmt.Sprintf("PAY-2024-%06d", rand.Intn(999999)+1),
+		ReferenceNumber: fmt.Sprintf("PAY-2024-%06d", g.rng.Intn(999999)+1),
 	}
 }
 
-func randomPaymentMethod() pb.PaymentMethod {
+func (g *generator) randomPaymentMethod() pb.PaymentMethod {
 	methods := []pb.PaymentMethod{
 		pb.PaymentMethod_PAYMENT_METHOD_CASH,
 		pb.PaymentMethod_PAYMENT_METHOD_CREDIT_CARD,
 		pb.PaymentMethod_PAYMENT_METHOD_BANK_TRANSFER,
 		pb.PaymentMethod_PAYMENT_METHOD_CREDIT,
 	}
-	return methods[rand.Intn(len(methods))]
+	return methods[g.rng.Intn(len(methods))]
 }
 
-func generateDeliveryInfo(orderDate *timestamppb.Timestamp) *pb.DeliveryInfo {
-	scheduledDate := timestamppb.New(orderDate.AsTime().AddDate(0, 0, rand.Intn(5)+1))
+func (g *generator) generateDeliveryInfo(orderDate *timestamppb.Timestamp) *pb.DeliveryInfo {
+	scheduledDate := timestamppb.New(orderDate.AsTime().AddDate(0, 0, g.rng.Intn(5)+1))
 	return &pb.DeliveryInfo{
-		DeliveryAddress: fmt.Sprintf("%d %s Street", rand.Intn(999)+1, randomChoice([]string{"Main", "Oak", "Pine", "Elm"})),
+		DeliveryAddress: g.randomStreetAddress(),
 		ScheduledDate:   scheduledDate,
 		ActualDate:      scheduledDate,
 		Status:          pb.DeliveryStatus_DELIVERY_STATUS_DELIVERED,
-		DeliveryNotes:   "Delivery completed successfully",
-		TrackingNumber:  fmt.Sprintf("TRK-2024-%06d", rand.Intn(999999)+1),
+		DeliveryNotes:   g.randomChoice(g.locale.DeliveryNotes),
+		TrackingNumber:  fmt.Sprintf("TRK-2024-%06d", g.rng.Intn(999999)+1),
 	}
 }
 
-func generateStatistics(topProductsCount int, orders []*pb.Order, visits []*pb.Visit) *pb.OutletStatistics {
+func (g *generator) generateStatistics(topProductsCount int, orders []*pb.Order, visits []*pb.Visit) *pb.OutletStatistics {
 	totalRevenue := 0.0
 	for _, order := range orders {
 		totalRevenue += order.TotalAmount
 	}
+	totalRevenue = g.market.Round(totalRevenue)
 
 	avgOrderValue := 0.0
 	if len(orders) > 0 {
-		avgOrderValue = totalRevenue / float64(len(orders))
+		avgOrderValue = g.market.Round(totalRevenue / float64(len(orders)))
 	}
 
 	return &pb.OutletStatistics{
 		TotalRevenueYtd:         totalRevenue,
-		TotalRevenueLastYear:    totalRevenue * (0.7 + rand.Float64()*0.6), // 70-130% of current
+		TotalRevenueLastYear:    g.market.Round(totalRevenue * (0.7 + g.rng.Float64()*0.6)), // 70-130% of current
 		AverageOrderValue:       avgOrderValue,
 		TotalOrdersYtd:          int32(len(orders)),
-		TotalOrdersLastYear:     int32(float64(len(orders)) * (0.7 + rand.Float64()*0.6)),
+		TotalOrdersLastYear:     int32(float64(len(orders)) * (0.7 + g.rng.Float64()*0.6)),
 		TotalVisitsYtd:          int32(len(visits)),
-		RevenueGrowthPercentage: rand.Float64()*50 - 10, // -10% to +40%
-		DaysSinceLastOrder:      int32(rand.Intn(30)),
-		DaysSinceLastVisit:      int32(rand.Intn(30)),
-		TopProducts:             generateTopProducts(topProductsCount),
-		MonthlyRevenue:          generateMonthlyRevenue(),
-		Segment:                 randomCustomerSegment(),
-		CreditInfo:              generateCreditInfo(),
+		RevenueGrowthPercentage: g.rng.Float64()*50 - 10, // -10% to +40%
+		DaysSinceLastOrder:      int32(g.rng.Intn(30)),
+		DaysSinceLastVisit:      int32(g.rng.Intn(30)),
+		TopProducts:             g.topProducts(topProductsCount, orders),
+		MonthlyRevenue:          g.monthlyRevenue(orders),
+		Segment:                 g.randomCustomerSegment(),
+		CreditInfo:              g.generateCreditInfo(),
+		Currency:                g.market.Currency,
 	}
 }
 
-func generateTopProducts(count int) []*pb.ProductStatistics {
+// topProducts ranks the products of the order history by net revenue
+func (g *generator) topProducts(count int, orders []*pb.Order) []*pb.ProductStatistics {
 	if count == 0 {
 		return nil
 	}
 
-	products := make([]*pb.ProductStatistics, count)
-	for i := 0; i < count; i++ {
-		quantitySold := int32(rand.Intn(1000) + 100)
-		revenue := float64(quantitySold) * (rand.Float64()*20 + 5)
-
-		products[i] = &pb.ProductStatistics{
-			ProductId:    fmt.Sprintf("prod-%03d", i+1),
-			ProductName:  randomChoice(productNames),
-			QuantitySold: quantitySold,
-			Revenue:      revenue,
-			OrdersCount:  int32(rand.Intn(20) + 5),
+	byProduct := map[string]*pb.ProductStatistics{}
+	for _, order := range orders {
+		seen := map[string]bool{}
+		for _, item := range order.Items {
+			stats, ok := byProduct[item.ProductId]
+			if !ok {
+				stats = &pb.ProductStatistics{ProductId: item.ProductId, ProductName: item.ProductName}
+				byProduct[item.ProductId] = stats
+			}
+			stats.QuantitySold += item.Quantity
+			stats.Revenue += item.TotalPrice
+			if !seen[item.ProductId] {
+				stats.OrdersCount++
+				seen[item.ProductId] = true
+			}
 		}
 	}
+
+	products := make([]*pb.ProductStatistics, 0, len(byProduct))
+	for _, stats := range byProduct {
+		stats.Revenue = g.market.Round(stats.Revenue)
+		products = append(products, stats)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].Revenue != products[j].Revenue {
+			return products[i].Revenue > products[j].Revenue
+		}
+		return products[i].ProductId < products[j].ProductId
+	})
+	if len(products) > count {
+		products = products[:count]
+	}
 	return products
 }
 
-func generateMonthlyRevenue() []*pb.MonthlyRevenue {
+// monthlyRevenue sums the order totals of the last six months, oldest first
+func (g *generator) monthlyRevenue(orders []*pb.Order) []*pb.MonthlyRevenue {
+	now := g.now
+	firstMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -5, 0)
+
 	months := make([]*pb.MonthlyRevenue, 6)
-	for i := 0; i < 6; i++ {
-		months[i] = &pb.MonthlyRevenue{
-			Year:        2024,
-			Month:       int32(i + 1),
-			Revenue:     rand.Float64()*5000 + 5000, // 5k-10k
-			OrdersCount: int32(rand.Intn(10) + 3),
+	for i := range months {
+		month := firstMonth.AddDate(0, i, 0)
+		months[i] = &pb.MonthlyRevenue{Year: int32(month.Year()), Month: int32(month.Month())}
+	}
+	for _, order := range orders {
+		date := order.OrderDate.AsTime()
+		index := (date.Year()-firstMonth.Year())*12 + int(date.Month()) - int(firstMonth.Month())
+		if index < 0 || index >= len(months) {
+			continue
 		}
+		months[index].Revenue += order.TotalAmount
+		months[index].OrdersCount++
+	}
+	for _, month := range months {
+		month.Revenue = g.market.Round(month.Revenue)
 	}
 	return months
 }
 
-func randomCustomerSegment() pb.CustomerSegment {
+func (g *generator) randomCustomerSegment() pb.CustomerSegment {
 	segments := []pb.CustomerSegment{
 		pb.CustomerSegment_CUSTOMER_SEGMENT_BRONZE,
 		pb.CustomerSegment_CUSTOMER_SEGMENT_SILVER,
 		pb.CustomerSegment_CUSTOMER_SEGMENT_GOLD,
 		pb.CustomerSegment_CUSTOMER_SEGMENT_PLATINUM,
 	}
-	return segments[rand.Intn(len(segments))]
+	return segments[g.rng.Intn(len(segments))]
 }
 
-func generateCreditInfo() *pb.CreditInfo {
-	creditLimit := float64(rand.Intn(50000) + 10000)
-	creditUsed := creditLimit * (rand.Float64() * 0.7) // Up to 70% used
+func (g *generator) generateCreditInfo() *pb.CreditInfo {
+	creditLimit := g.market.Scale(float64(g.rng.Intn(50000) + 10000))
+	creditUsed := g.market.Round(creditLimit * (g.rng.Float64() * 0.7)) // Up to 70% used
 
 	return &pb.CreditInfo{
 		CreditLimit:      creditLimit,
 		CreditUsed:       creditUsed,
-		CreditAvailable:  creditLimit - creditUsed,
-		PaymentTermsDays: int32(rand.Intn(60) + 15), // 15-75 days
+		CreditAvailable:  g.market.Round(creditLimit - creditUsed),
+		PaymentTermsDays: int32(g.rng.Intn(60) + 15), // 15-75 days
 		Status:           pb.CreditStatus_CREDIT_STATUS_GOOD,
 	}
 }
 
-func generateNearbyOutlets(count int, baseLocation *pb.Location) []*pb.OutletNearby {
+func (g *generator) generateNearbyOutlets(count int, baseLocation *pb.Location) []*pb.OutletNearby {
 	outlets := make([]*pb.OutletNearby, count)
 	for i := 0; i < count; i++ {
 		outlets[i] = &pb.OutletNearby{
 			OutletId:     fmt.Sprintf("nearby-outlet-%03d", i+1),
-			Name:         randomChoice(outletNames),
-			Type:         randomOutletType(),
-			DistanceKm:   rand.Float64()*5 + 0.1, // 0.1 to 5.1 km
-			Location:     generateNearbyLocation(baseLocation),
-			IsCompetitor: rand.Float64() < 0.3, // 30% chance of competitor
-			Relationship: randomChoice([]string{"Partner", "Competitor", "Neutral", "Supplier"}),
+			Name:         g.randomChoice(g.locale.OutletNames),
+			Type:         g.randomOutletType(),
+			DistanceKm:   g.rng.Float64()*5 + 0.1, // 0.1 to 5.1 km
+			Location:     g.generateNearbyLocation(baseLocation),
+			IsCompetitor: g.rng.Float64() < 0.3, // 30% chance of competitor
+			Relationship: g.randomChoice(g.locale.Relationships),
 			Thumbnail:    "https://picsum.photos/100",
 		}
 	}
 	return outlets
 }
 
-func generateNearbyLocation(base *pb.Location) *pb.Location {
+func (g *generator) generateNearbyLocation(base *pb.Location) *pb.Location {
 	return &pb.Location{
-		Address:   fmt.Sprintf("%d %s Avenue", rand.Intn(999)+1, randomChoice([]string{"Park", "Hill", "River", "Lake"})),
+		Address:   g.randomStreetAddress(),
 		City:      base.City,
 		State:     base.State,
-		Latitude:  base.Latitude + (rand.Float64()-0.5)*0.05,
-		Longitude: base.Longitude + (rand.Float64()-0.5)*0.05,
+		Country:   base.Country,
+		Latitude:  base.Latitude + (g.rng.Float64()-0.5)*0.05,
+		Longitude: base.Longitude + (g.rng.Float64()-0.5)*0.05,
 	}
 }
 
-func generateNotes(count int) []*pb.Note {
+func (g *generator) generateNotes(count int) []*pb.Note {
 	notes := make([]*pb.Note, count)
 	for i := 0; i < count; i++ {
-		createdAt := timestamppb.New(time.Now().AddDate(0, 0, -(rand.Intn(90))))
+		createdAt := timestamppb.New(g.now.AddDate(0, 0, -(g.rng.Intn(90))))
 
 		notes[i] = &pb.Note{
 			NoteId:    fmt.Sprintf("note-%03d", i+1),
-			Title:     randomChoice([]string{"Customer Feedback", "Sales Opportunity", "Support Issue", "Follow-up Required", "Payment Discussion"}),
-			Content:   fmt.Sprintf("Note content %d: %s", i+1, randomChoice([]string{"Customer showed interest in new products", "Discussed pricing options", "Resolved technical issue", "Scheduled follow-up meeting"})),
-			Type:      noteTypes[rand.Intn(len(noteTypes))],
-			CreatedBy: fmt.Sprintf("rep-%03d", rand.Intn(10)+1),
+			Title:     g.randomChoice(g.locale.NoteTitles),
+			Content:   g.format(g.randomChoice(g.locale.NoteContents), "n", fmt.Sprint(i+1)),
+			Type:      noteTypes[g.rng.Intn(len(noteTypes))],
+			CreatedBy: fmt.Sprintf("rep-%03d", g.rng.Intn(10)+1),
 			CreatedAt: createdAt,
 			UpdatedAt: createdAt,
-			IsPrivate: rand.Float64() < 0.2, // 20% chance of private
-			Tags:      []string{randomChoice([]string{"urgent", "follow-up", "opportunity", "issue", "pricing"})},
+			IsPrivate: g.rng.Float64() < 0.2, // 20% chance of private
+			Tags:      []string{g.randomChoice(g.locale.NoteTags)},
 		}
 	}
 	return notes
 }
 
-func generateAssets(count int) []*pb.Asset {
+func (g *generator) generateAssets(count int) []*pb.Asset {
 	assets := make([]*pb.Asset, count)
 	for i := 0; i < count; i++ {
-		installDate := timestamppb.New(time.Now().AddDate(-rand.Intn(3)-1, 0, 0))
-		lastMaintenance := timestamppb.New(time.Now().AddDate(0, -rand.Intn(6)-1, 0))
+		installDate := timestamppb.New(g.now.AddDate(-g.rng.Intn(3)-1, 0, 0))
+		lastMaintenance := timestamppb.New(g.now.AddDate(0, -g.rng.Intn(6)-1, 0))
 
 		assets[i] = &pb.Asset{
 			AssetId:             fmt.Sprintf("asset-%03d", i+1),
-			Name:                fmt.Sprintf("%s Unit #%d", randomChoice([]string{"Cooler", "Freezer", "Display", "POS System", "Shelving"}), i+1),
-			Type:                assetTypes[rand.Intn(len(assetTypes))],
-			Model:               fmt.Sprintf("Model-%s-%d", randomString(3), rand.Intn(999)+100),
-			SerialNumber:        fmt.Sprintf("SN-%d-%06d", 2023+rand.Intn(2), rand.Intn(999999)+1),
+			Name:                fmt.Sprintf("%s #%d", g.randomChoice(g.locale.AssetNames), i+1),
+			Type:                assetTypes[g.rng.Intn(len(assetTypes))],
+			Model:               fmt.Sprintf("Model-%s-%d", g.randomString(3), g.rng.Intn(999)+100),
+			SerialNumber:        fmt.Sprintf("SN-%d-%06d", 2023+g.rng.Intn(2), g.rng.Intn(999999)+1),
 			Status:              pb.AssetStatus_ASSET_STATUS_ACTIVE,
 			InstallationDate:    installDate,
 			LastMaintenanceDate: lastMaintenance,
-			NextMaintenanceDate: timestamppb.New(time.Now().AddDate(0, rand.Intn(6)+1, 0)),
-			LocationDetails:     fmt.Sprintf("Aisle %d, Section %s", rand.Intn(20)+1, randomChoice([]string{"A", "B", "C", "D"})),
-			Condition:           randomChoice([]string{"Excellent", "Good", "Fair", "Needs Attention"}),
-			MaintenanceHistory:  generateMaintenanceHistory(rand.Intn(3) + 1),
+			NextMaintenanceDate: timestamppb.New(g.now.AddDate(0, g.rng.Intn(6)+1, 0)),
+			LocationDetails:     g.randomAssetLocation(),
+			Condition:           g.randomChoice(g.locale.AssetConditions),
+			MaintenanceHistory:  g.generateMaintenanceHistory(g.rng.Intn(3) + 1),
 		}
 	}
 	return assets
 }
 
-func generateMaintenanceHistory(count int) []*pb.AssetMaintenance {
+func (g *generator) randomAssetLocation() string {
+	return g.format(g.locale.AssetLocationFormat, "aisle", fmt.Sprint(g.rng.Intn(20)+1), "section", g.randomChoice([]string{"A", "B", "C", "D"}))
+}
+
+func (g *generator) generateMaintenanceHistory(count int) []*pb.AssetMaintenance {
 	history := make([]*pb.AssetMaintenance, count)
 	for i := 0; i < count; i++ {
 		history[i] = &pb.AssetMaintenance{
-			Date:        timestamppb.New(time.Now().AddDate(0, -rand.Intn(12)-1, 0)),
-			Type:        randomMaintenanceType(),
-			Description: randomChoice([]string{"Routine cleaning", "Temperature calibration", "Parts replacement", "Software update"}),
-			Technician:  randomChoice([]string{"Tech Services Inc", "Maintenance Pro", "Equipment Care Ltd"}),
-			Cost:        rand.Float64()*500 + 50, // $50-$550
+			Date:        timestamppb.New(g.now.AddDate(0, -g.rng.Intn(12)-1, 0)),
+			Type:        g.randomMaintenanceType(),
+			Description: g.randomChoice(g.locale.MaintenanceDescriptions),
+			Technician:  g.randomChoice(g.locale.MaintenanceTechnicians),
+			Cost:        g.market.Scale(g.rng.Float64()*500 + 50), // $50-$550 in the market's currency
 		}
 	}
 	return history
 }
 
-func randomMaintenanceType() pb.MaintenanceType {
+func (g *generator) randomMaintenanceType() pb.MaintenanceType {
 	types := []pb.MaintenanceType{
 		pb.MaintenanceType_MAINTENANCE_TYPE_ROUTINE,
 		pb.MaintenanceType_MAINTENANCE_TYPE_REPAIR,
 		pb.MaintenanceType_MAINTENANCE_TYPE_REPLACEMENT,
 		pb.MaintenanceType_MAINTENANCE_TYPE_UPGRADE,
 	}
-	return types[rand.Intn(len(types))]
+	return types[g.rng.Intn(len(types))]
 }
 
-func generateChecklist(count int) []*pb.ChecklistItem {
+func (g *generator) generateChecklist(count int) []*pb.ChecklistItem {
 	items := make([]*pb.ChecklistItem, count)
 	for i := 0; i < count; i++ {
-		dueDate := timestamppb.New(time.Now().AddDate(0, 0, rand.Intn(30)-15)) // -15 to +15 days
-		isCompleted := rand.Float64() < 0.6                                    // 60% completion rate
+		dueDate := timestamppb.New(g.now.AddDate(0, 0, g.rng.Intn(30)-15)) // -15 to +15 days
+		isCompleted := g.rng.Float64() < 0.6                               // 60% completion rate
 
 		var completedDate *timestamppb.Timestamp
 		var status pb.ChecklistStatus
 
 		if isCompleted {
-			completedDate = timestamppb.New(dueDate.AsTime().AddDate(0, 0, -rand.Intn(5)))
+			completedDate = timestamppb.New(dueDate.AsTime().AddDate(0, 0, -g.rng.Intn(5)))
 			status = pb.ChecklistStatus_CHECKLIST_STATUS_COMPLETED
-		} else if dueDate.AsTime().Before(time.Now()) {
+		} else if dueDate.AsTime().Before(g.now) {
 			status = pb.ChecklistStatus_CHECKLIST_STATUS_OVERDUE
 		} else {
 			status = pb.ChecklistStatus_CHECKLIST_STATUS_PENDING
@@ -602,59 +800,59 @@ func generateChecklist(count int) []*pb.ChecklistItem {
 
 		items[i] = &pb.ChecklistItem{
 			ItemId:        fmt.Sprintf("check-%03d", i+1),
-			Title:         randomChoice([]string{"Display Compliance", "Inventory Check", "Safety Inspection", "Quality Review", "Marketing Setup"}),
-			Description:   fmt.Sprintf("Checklist item %d description", i+1),
-			Category:      checklistCategories[rand.Intn(len(checklistCategories))],
+			Title:         g.randomChoice(g.locale.ChecklistTitles),
+			Description:   g.format(g.locale.ChecklistDescriptionFmt, "n", fmt.Sprint(i+1)),
+			Category:      checklistCategories[g.rng.Intn(len(checklistCategories))],
 			Status:        status,
-			Priority:      randomPriority(),
+			Priority:      g.randomPriority(),
 			DueDate:       dueDate,
 			CompletedDate: completedDate,
-			AssignedTo:    fmt.Sprintf("rep-%03d", rand.Intn(10)+1),
+			AssignedTo:    fmt.Sprintf("rep-%03d", g.rng.Intn(10)+1),
 			CompletedBy: func() string {
 				if isCompleted {
-					return fmt.Sprintf("rep-%03d", rand.Intn(10)+1)
+					return fmt.Sprintf("rep-%03d", g.rng.Intn(10)+1)
 				} else {
 					return ""
 				}
 			}(),
-			Notes: randomChoice([]string{"Standard procedure", "Special attention required", "Follow brand guidelines", "Coordinate with manager"}),
+			Notes: g.randomChoice(g.locale.ChecklistNotes),
 		}
 	}
 	return items
 }
 
-func randomPriority() pb.Priority {
+func (g *generator) randomPriority() pb.Priority {
 	priorities := []pb.Priority{
 		pb.Priority_PRIORITY_LOW,
 		pb.Priority_PRIORITY_MEDIUM,
 		pb.Priority_PRIORITY_HIGH,
 		pb.Priority_PRIORITY_CRITICAL,
 	}
-	return priorities[rand.Intn(len(priorities))]
+	return priorities[g.rng.Intn(len(priorities))]
 }
 
-func generateNews(count int) []*pb.News {
+func (g *generator) generateNews(count int) []*pb.News {
 	news := make([]*pb.News, count)
 	for i := 0; i < count; i++ {
-		publishDate := timestamppb.New(time.Now().AddDate(0, 0, -(rand.Intn(30))))
+		publishDate := timestamppb.New(g.now.AddDate(0, 0, -(g.rng.Intn(30))))
 
 		news[i] = &pb.News{
 			NewsId:        fmt.Sprintf("news-%03d", i+1),
-			Title:         randomChoice([]string{"Product Launch", "Market Update", "Policy Change", "Promotion Alert", "Training Available"}),
-			Content:       fmt.Sprintf("News content %d: Important information about recent developments.", i+1),
-			Type:          randomNewsType(),
-			Source:        randomNewsSource(),
+			Title:         g.randomChoice(g.locale.NewsTitles),
+			Content:       g.format(g.locale.NewsContentFormat, "n", fmt.Sprint(i+1)),
+			Type:          g.randomNewsType(),
+			Source:        g.randomNewsSource(),
 			PublishedDate: publishDate,
-			Author:        randomChoice([]string{"Marketing Team", "Sales Department", "Management", "External Source"}),
-			Url:           fmt.Sprintf("https://company.com/news/article-%d", i+1),
-			Tags:          []string{randomChoice([]string{"product", "sales", "market", "policy", "training"})},
-			IsImportant:   rand.Float64() < 0.3, // 30% chance of important
+			Author:        g.randomChoice(g.locale.NewsAuthors),
+			Url:           g.format(g.locale.NewsURL, "n", fmt.Sprint(i+1)),
+			Tags:          []string{g.randomChoice(g.locale.NewsTags)},
+			IsImportant:   g.rng.Float64() < 0.3, // 30% chance of important
 		}
 	}
 	return news
 }
 
-func randomNewsType() pb.NewsType {
+func (g *generator) randomNewsType() pb.NewsType {
 	types := []pb.NewsType{
 		pb.NewsType_NEWS_TYPE_GENERAL,
 		pb.NewsType_NEWS_TYPE_PROMOTION,
@@ -663,17 +861,17 @@ func randomNewsType() pb.NewsType {
 		pb.NewsType_NEWS_TYPE_MARKET_UPDATE,
 		pb.NewsType_NEWS_TYPE_COMPETITOR,
 	}
-	return types[rand.Intn(len(types))]
+	return types[g.rng.Intn(len(types))]
 }
 
-func randomNewsSource() pb.NewsSource {
+func (g *generator) randomNewsSource() pb.NewsSource {
 	sources := []pb.NewsSource{
 		pb.NewsSource_NEWS_SOURCE_INTERNAL,
 		pb.NewsSource_NEWS_SOURCE_EXTERNAL,
 		pb.NewsSource_NEWS_SOURCE_OUTLET,
 		pb.NewsSource_NEWS_SOURCE_MARKET_RESEARCH,
 	}
-	return sources[rand.Intn(len(sources))]
+	return sources[g.rng.Intn(len(sources))]
 }
 
 // Legacy function for backward compatibility
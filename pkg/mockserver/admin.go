@@ -0,0 +1,375 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"srv-eazle-advise-mock/pkg/conflict"
+	"srv-eazle-advise-mock/pkg/fault"
+	"srv-eazle-advise-mock/pkg/journal"
+	"srv-eazle-advise-mock/pkg/latency"
+	"srv-eazle-advise-mock/pkg/ratelimit"
+	"srv-eazle-advise-mock/pkg/scenario"
+	"srv-eazle-advise-mock/pkg/stub"
+)
+
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.store == nil {
+		http.Error(w, ErrNotStateful.Error(), http.StatusConflict)
+		return
+	}
+
+	// Only the configured file is written, callers of the admin API don't pick paths
+	path := s.snapshotPath
+	if path == "" {
+		http.Error(w, "No snapshot path configured", http.StatusBadRequest)
+		return
+	}
+
+	outlets, err := s.Snapshot(path)
+	if err != nil {
+		http.Error(w, "Error saving snapshot", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"path": path, "outlets": outlets})
+}
+
+// handleFaults manages fault injection rules:
+// GET lists them, POST adds one, DELETE removes one (?id=) or all of them
+func (s *Server) handleFaults(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.faults.Rules())
+	case http.MethodPost:
+		var rule fault.Rule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			http.Error(w, "Invalid fault rule", http.StatusBadRequest)
+			return
+		}
+		rule, err := s.faults.Add(rule)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, rule)
+	case http.MethodDelete:
+		if id := r.URL.Query().Get("id"); id != "" {
+			if !s.faults.Remove(id) {
+				http.Error(w, "Fault rule not found", http.StatusNotFound)
+				return
+			}
+		} else {
+			s.faults.Clear()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleLatency manages latency profiles and route bindings:
+// GET returns them, POST applies a latency.Config, DELETE resets to the built-in profiles
+func (s *Server) handleLatency(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.latency.Config())
+	case http.MethodPost:
+		var config latency.Config
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			http.Error(w, "Invalid latency config", http.StatusBadRequest)
+			return
+		}
+		if err := s.latency.Apply(config); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, s.latency.Config())
+	case http.MethodDelete:
+		s.latency.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleScenarios manages scenario timelines:
+// GET lists them, POST adds or replaces one, DELETE removes one (?name=) or all of them
+func (s *Server) handleScenarios(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.scenarios.Scenarios())
+	case http.MethodPost:
+		var sc scenario.Scenario
+		if err := json.NewDecoder(r.Body).Decode(&sc); err != nil {
+			http.Error(w, "Invalid scenario", http.StatusBadRequest)
+			return
+		}
+		if err := s.scenarios.Put(sc); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, sc)
+	case http.MethodDelete:
+		if name := r.URL.Query().Get("name"); name != "" {
+			if !s.scenarios.Remove(name) {
+				http.Error(w, "Scenario not found", http.StatusNotFound)
+				return
+			}
+		} else {
+			s.scenarios.Clear()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleScenariosReset restarts scenarios from their first step,
+// optionally limited to one scenario (?name=) and/or client (?client=)
+func (s *Server) handleScenariosReset(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.scenarios.Reset(r.URL.Query().Get("name"), r.URL.Query().Get("client"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRateLimits manages rate limits: GET returns the limits and the state of
+// every bucket, POST adds or replaces a limit, DELETE removes one (?key=&route=) or all of them
+func (s *Server) handleRateLimits(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{
+			"limits":  s.limiter.Limits(),
+			"buckets": s.limiter.Buckets(),
+		})
+	case http.MethodPost:
+		var limit ratelimit.Limit
+		if err := json.NewDecoder(r.Body).Decode(&limit); err != nil {
+			http.Error(w, "Invalid rate limit", http.StatusBadRequest)
+			return
+		}
+		if err := s.limiter.Put(limit); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, limit)
+	case http.MethodDelete:
+		query := r.URL.Query()
+		if query.Has("key") || query.Has("route") {
+			if !s.limiter.Remove(query.Get("key"), query.Get("route")) {
+				http.Error(w, "Rate limit not found", http.StatusNotFound)
+				return
+			}
+		} else {
+			s.limiter.Clear()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleRequests returns the journaled requests (GET) or clears the journal (DELETE).
+// GET filters on ?method=, ?path=, ?pathPrefix= and ?header=Name:value, which may repeat.
+func (s *Server) handleRequests(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		filter := journal.Filter{
+			Method:     query.Get("method"),
+			Path:       query.Get("path"),
+			PathPrefix: query.Get("pathPrefix"),
+			Headers:    map[string]string{},
+		}
+		for _, header := range query["header"] {
+			name, value, _ := strings.Cut(header, ":")
+			filter.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		requests := s.journal.Find(filter)
+		writeJSON(w, http.StatusOK, map[string]any{"requests": requests, "total": len(requests)})
+	case http.MethodDelete:
+		s.journal.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleRequestsCount counts the journaled requests matching a journal.Filter
+func (s *Server) handleRequestsCount(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var filter journal.Filter
+	if err := json.NewDecoder(r.Body).Decode(&filter); err != nil {
+		http.Error(w, "Invalid request filter", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"count": s.journal.Count(filter)})
+}
+
+// handleMappings manages stub mappings:
+// GET lists them, POST adds one, DELETE removes one (?id=) or all of them
+func (s *Server) handleMappings(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.stubs.Mappings())
+	case http.MethodPost:
+		var mapping stub.Mapping
+		if err := json.NewDecoder(r.Body).Decode(&mapping); err != nil {
+			http.Error(w, "Invalid stub mapping", http.StatusBadRequest)
+			return
+		}
+		mapping, err := s.stubs.Add(mapping)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, mapping)
+	case http.MethodDelete:
+		if id := r.URL.Query().Get("id"); id != "" {
+			if !s.stubs.Remove(id) {
+				http.Error(w, "Stub mapping not found", http.StatusNotFound)
+				return
+			}
+		} else {
+			s.stubs.Clear()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCache returns the response cache statistics (GET) or flushes it (DELETE)
+func (s *Server) handleCache(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.CacheStats())
+	case http.MethodDelete:
+		s.FlushCache()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleConflicts manages forced conflicts on writes:
+// GET lists the rules, POST adds a conflict.Rule, DELETE removes one (?id=) or all
+func (s *Server) handleConflicts(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.conflicts.Rules())
+	case http.MethodPost:
+		var rule conflict.Rule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			http.Error(w, "Invalid conflict rule", http.StatusBadRequest)
+			return
+		}
+		rule, err := s.conflicts.Add(rule)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusCreated, rule)
+	case http.MethodDelete:
+		if id := r.URL.Query().Get("id"); id != "" {
+			if !s.conflicts.Remove(id) {
+				http.Error(w, "Conflict rule not found", http.StatusNotFound)
+				return
+			}
+		} else {
+			s.conflicts.Clear()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleIdempotency returns the number of stored idempotency keys (GET) or forgets them (DELETE)
+func (s *Server) handleIdempotency(w http.ResponseWriter, r *http.Request) {
+	if !s.validateSecretKey(r) {
+		http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]int{"keys": s.idempotency.Len()})
+	case http.MethodDelete:
+		s.ResetIdempotencyKeys()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
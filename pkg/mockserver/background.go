@@ -0,0 +1,104 @@
+package mockserver
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/mock"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// startBackgroundChanges simulates changesPerMinute changes to the universe
+// until the returned function is called
+func (s *Server) startBackgroundChanges(changesPerMinute float64) func() {
+	ticker := time.NewTicker(time.Duration(float64(time.Minute) / changesPerMinute))
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.SimulateChange()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// SimulateChange makes one random change to the universe like a field rep
+// would: an outlet update or a note, visit, order or checklist item being
+// added, updated or deleted
+func (s *Server) SimulateChange() error {
+	if s.store == nil {
+		return ErrNotStateful
+	}
+	s.writes.Lock()
+	defer s.writes.Unlock()
+
+	ids := s.store.IDs()
+	if len(ids) == 0 {
+		return nil
+	}
+	outlet, ok := s.store.Get(ids[rand.Intn(len(ids))])
+	if !ok {
+		return nil
+	}
+	settings := s.Settings()
+	now := timestamppb.Now()
+
+	switch rand.Intn(8) {
+	case 0:
+		if outlet.Status == pb.OutletStatus_OUTLET_STATUS_ACTIVE {
+			outlet.Status = pb.OutletStatus_OUTLET_STATUS_INACTIVE
+		} else {
+			outlet.Status = pb.OutletStatus_OUTLET_STATUS_ACTIVE
+		}
+		outlet.UpdatedAt = now
+		s.store.Put(outlet)
+		return nil
+	case 1:
+		if len(outlet.Notes) > 0 {
+			note := outlet.Notes[rand.Intn(len(outlet.Notes))]
+			note.Content = mock.GenerateNote(note.NoteId, settings).Content
+			note.UpdatedAt = now
+			return s.store.PutRecord(outlet.OutletId, note)
+		}
+	case 2:
+		if len(outlet.Notes) > 0 {
+			note := outlet.Notes[rand.Intn(len(outlet.Notes))]
+			s.store.DeleteRecord(outlet.OutletId, pb.SyncRecordType_SYNC_RECORD_TYPE_NOTE, note.NoteId)
+			return nil
+		}
+	case 3:
+		return s.store.PutRecord(outlet.OutletId, mock.GenerateVisit(syncRecordID("visit"), settings))
+	case 4:
+		return s.store.PutRecord(outlet.OutletId, mock.GenerateOrder(syncRecordID("order"), settings))
+	case 5:
+		if len(outlet.Checklist) > 0 {
+			item := outlet.Checklist[rand.Intn(len(outlet.Checklist))]
+			item.Status = pb.ChecklistStatus_CHECKLIST_STATUS_COMPLETED
+			item.CompletedDate = now
+			return s.store.PutRecord(outlet.OutletId, item)
+		}
+	case 6:
+		if len(outlet.Checklist) > 0 {
+			item := outlet.Checklist[rand.Intn(len(outlet.Checklist))]
+			s.store.DeleteRecord(outlet.OutletId, pb.SyncRecordType_SYNC_RECORD_TYPE_CHECKLIST_ITEM, item.ItemId)
+			return nil
+		}
+	}
+	// New notes are also the fallback for outlets without the records to change
+	return s.store.PutRecord(outlet.OutletId, mock.GenerateNote(syncRecordID("note"), settings))
+}
+
+// syncRecordID is random so records created before a snapshot restore are not overwritten
+func syncRecordID(prefix string) string {
+	return fmt.Sprintf("%s-sync-%08x", prefix, rand.Uint32())
+}
@@ -0,0 +1,309 @@
+package mockserver
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"srv-eazle-advise-mock/pkg/fault"
+	"srv-eazle-advise-mock/pkg/fieldmask"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/mock"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// collection is a repeated field of OutletDetails served page by page
+type collection struct {
+	field     protoreflect.Name // in OutletDetails
+	idField   protoreflect.Name
+	dateField protoreflect.Name // filtered by from and to, sorted by sort=date
+	// newPage returns the List*Response, with the records as field 1,
+	// next_page_token as field 2 and total_size as field 3
+	newPage func() proto.Message
+	create  *resource // set when records can be created with POST
+}
+
+var (
+	visitsCollection    = collection{"visit_history", "visit_id", "visit_date", func() proto.Message { return &pb.ListVisitsResponse{} }, &visitResource}
+	ordersCollection    = collection{"order_history", "order_id", "order_date", func() proto.Message { return &pb.ListOrdersResponse{} }, &orderResource}
+	notesCollection     = collection{"notes", "note_id", "created_at", func() proto.Message { return &pb.ListNotesResponse{} }, &noteResource}
+	assetsCollection    = collection{"asset_list", "asset_id", "installation_date", func() proto.Message { return &pb.ListAssetsResponse{} }, nil}
+	checklistCollection = collection{"checklist", "item_id", "due_date", func() proto.Message { return &pb.ListChecklistItemsResponse{} }, nil}
+	newsCollection      = collection{"news", "news_id", "published_date", func() proto.Message { return &pb.ListNewsResponse{} }, nil}
+)
+
+func (s *Server) handleVisits(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveCollection(w, r, visitsCollection, r.PathValue("outletID"), networkFault)
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveCollection(w, r, ordersCollection, r.PathValue("outletID"), networkFault)
+}
+
+func (s *Server) handleNotes(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveCollection(w, r, notesCollection, r.PathValue("outletID"), networkFault)
+}
+
+func (s *Server) handleAssets(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveCollection(w, r, assetsCollection, r.PathValue("outletID"), networkFault)
+}
+
+func (s *Server) handleChecklist(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveCollection(w, r, checklistCollection, r.PathValue("outletID"), networkFault)
+}
+
+func (s *Server) handleNews(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveCollection(w, r, newsCollection, r.PathValue("outletID"), networkFault)
+}
+
+// serveCollection lists the records of an outlet page by page (GET) or
+// creates one in stateful mode (POST). Creates honor an Idempotency-Key, see
+// idempotent.
+func (s *Server) serveCollection(w http.ResponseWriter, r *http.Request, c collection, outletID string, networkFault fault.Rule) {
+	switch {
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		s.listCollection(w, r, c, outletID, networkFault)
+	case r.Method == http.MethodPost && c.create != nil:
+		if s.store == nil {
+			http.Error(w, "Records are only available in stateful mode", http.StatusConflict)
+			return
+		}
+		s.idempotent(w, r, networkFault, func(w http.ResponseWriter, r *http.Request) {
+			s.createRecord(w, r, *c.create, outletID)
+		})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// pageToken continues a listing after the last record of the previous page
+type pageToken struct {
+	Sort string `json:"s"`
+	Date int64  `json:"d,omitempty"` // of the last record, in Unix nanoseconds
+	ID   string `json:"i"`           // of the last record
+	Seed int64  `json:"r,omitempty"` // generates the same outlet for every page
+}
+
+// pageKey is what records are sorted by and compared to a pageToken with
+type pageKey struct {
+	date int64
+	id   string
+}
+
+// listCollection sends a page of a collection, filtered by the from and to
+// query parameters and sorted by sort (date, -date, id or -id, default -date).
+// Pages continue after the last record of the previous one, so records
+// created or deleted meanwhile neither shift nor repeat the following pages.
+func (s *Server) listCollection(w http.ResponseWriter, r *http.Request, c collection, outletID string, networkFault fault.Rule) {
+	query := r.URL.Query()
+	pageSize := defaultPageSize
+	if value := query.Get("page_size"); value != "" {
+		var err error
+		pageSize, err = strconv.Atoi(value)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			http.Error(w, "Invalid page_size, use 1 to 100", http.StatusBadRequest)
+			return
+		}
+	}
+	sortBy := query.Get("sort")
+	if sortBy == "" {
+		sortBy = "-date"
+	}
+	if sortBy != "date" && sortBy != "-date" && sortBy != "id" && sortBy != "-id" {
+		http.Error(w, "Invalid sort, use date, -date, id or -id", http.StatusBadRequest)
+		return
+	}
+	from, err := parseDateBound(query.Get("from"), false)
+	if err != nil {
+		http.Error(w, "Invalid from, use RFC 3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDateBound(query.Get("to"), true)
+	if err != nil {
+		http.Error(w, "Invalid to, use RFC 3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	mask, err := requestMask(r, (&pb.OutletDetails{}).ProtoReflect().Descriptor().Fields().ByName(c.field).Message())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	token := pageToken{Sort: sortBy}
+	after := query.Get("page_token")
+	if after != "" {
+		if token, err = decodePageToken(after); err != nil || token.Sort != sortBy {
+			http.Error(w, "Invalid page_token", http.StatusBadRequest)
+			return
+		}
+	}
+
+	outlet, ok := s.collectionOutlet(w, r, c, outletID, &token)
+	if !ok {
+		return
+	}
+	outletFields := outlet.ProtoReflect().Descriptor().Fields()
+	records := outlet.ProtoReflect().Get(outletFields.ByName(c.field)).List()
+	recordFields := outletFields.ByName(c.field).Message().Fields()
+	idField, dateField := recordFields.ByName(c.idField), recordFields.ByName(c.dateField)
+
+	keyOf := func(record protoreflect.Message) pageKey {
+		key := pageKey{id: record.Get(idField).String()}
+		if record.Has(dateField) {
+			key.date = record.Get(dateField).Message().Interface().(*timestamppb.Timestamp).AsTime().UnixNano()
+		}
+		return key
+	}
+	compare := func(a, b pageKey) int {
+		result := 0
+		if strings.HasSuffix(sortBy, "date") {
+			result = cmp.Compare(a.date, b.date)
+		}
+		if result == 0 {
+			result = strings.Compare(a.id, b.id)
+		}
+		if strings.HasPrefix(sortBy, "-") {
+			result = -result
+		}
+		return result
+	}
+
+	var matching []protoreflect.Message
+	for i := 0; i < records.Len(); i++ {
+		record := records.Get(i).Message()
+		date := keyOf(record).date
+		if (from != nil && date < from.UnixNano()) || (to != nil && date >= to.UnixNano()) {
+			continue
+		}
+		matching = append(matching, record)
+	}
+	slices.SortStableFunc(matching, func(a, b protoreflect.Message) int { return compare(keyOf(a), keyOf(b)) })
+
+	start := 0
+	if after != "" {
+		last := pageKey{date: token.Date, id: token.ID}
+		start = len(matching)
+		for i, record := range matching {
+			if compare(keyOf(record), last) > 0 {
+				start = i
+				break
+			}
+		}
+	}
+	end := min(start+pageSize, len(matching))
+
+	page := c.newPage().ProtoReflect()
+	pageFields := page.Descriptor().Fields()
+	items := page.Mutable(pageFields.ByNumber(1)).List()
+	for _, record := range matching[start:end] {
+		mask.Prune(record.Interface())
+		items.Append(protoreflect.ValueOfMessage(record))
+	}
+	if end < len(matching) {
+		last := keyOf(matching[end-1])
+		token.Date, token.ID = last.date, last.id
+		page.Set(pageFields.ByNumber(2), protoreflect.ValueOfString(encodePageToken(token)))
+	}
+	page.Set(pageFields.ByNumber(3), protoreflect.ValueOfInt32(int32(len(matching))))
+
+	data, err := encodeResponse(r, page.Interface())
+	if err != nil {
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+	writeEncoded(w, r, encodedContentType(r), newEntry(data, nil), networkFault)
+}
+
+// collectionOutlet returns the outlet whose collection is listed: from the
+// universe in stateful mode, otherwise generated like /outlets?outlet_id= does
+// with the same X-Seed. Without X-Seed a random seed is kept in the page token,
+// so all pages come from the same outlet, and only the listed collection is generated.
+func (s *Server) collectionOutlet(w http.ResponseWriter, r *http.Request, c collection, outletID string, token *pageToken) (*pb.OutletDetails, bool) {
+	if s.store != nil {
+		outlet, ok := s.store.Get(outletID)
+		if !ok {
+			http.Error(w, "Outlet not found", http.StatusNotFound)
+		}
+		return outlet, ok
+	}
+
+	settings := s.Settings()
+	if acceptLanguage := r.Header.Get("Accept-Language"); acceptLanguage != "" {
+		settings.Locale = mock.MatchLocale(acceptLanguage)
+	}
+	seed := token.Seed
+	if seedHeader := r.Header.Get("X-Seed"); seedHeader != "" {
+		var err error
+		if seed, err = strconv.ParseInt(seedHeader, 10, 64); err != nil {
+			http.Error(w, "Invalid X-Seed header", http.StatusBadRequest)
+			return nil, false
+		}
+	} else {
+		if seed == 0 {
+			seed = rand.Int63n(1<<62) + 1
+			token.Seed = seed
+		}
+		only, _ := fieldmask.Parse((&pb.OutletDetails{}).ProtoReflect().Descriptor(), []string{string(c.field)})
+		settings = maskSettings(settings, only)
+	}
+
+	// The same seeds as the first outlets of /outlets: fixtures, then generated outlets
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for i, fixture := range s.fixtures.Outlets {
+		if fixture.OutletId == outletID {
+			return mock.CompleteSeededOutlet(proto.Clone(fixture).(*pb.OutletDetails), settings, seed+int64(i), today), true
+		}
+	}
+	return mock.CompleteSeededOutlet(&pb.OutletDetails{OutletId: outletID}, settings, seed+int64(len(s.fixtures.Outlets)), today), true
+}
+
+// parseDateBound reads a from or to parameter as RFC 3339 or a date. A date
+// as upper bound includes the whole day.
+func parseDateBound(value string, upper bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		if upper {
+			// to is inclusive, the comparison is not
+			t = t.Add(time.Nanosecond)
+		}
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+func encodePageToken(token pageToken) string {
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(value string) (pageToken, error) {
+	var token pageToken
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return token, err
+	}
+	err = json.Unmarshal(data, &token)
+	return token, err
+}
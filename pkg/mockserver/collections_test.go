@@ -0,0 +1,93 @@
+package mockserver
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+)
+
+func TestPageToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token pageToken
+	}{
+		{name: "sorted by date", token: pageToken{Sort: "-date", Date: 1767225600000000000, ID: "news-007"}},
+		{name: "sorted by id", token: pageToken{Sort: "id", ID: "note-012"}},
+		{name: "with seed", token: pageToken{Sort: "date", Date: 1, ID: "visit-001", Seed: -42}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := encodePageToken(tt.token)
+			if strings.ContainsAny(encoded, "+/=") {
+				t.Errorf("token %q is not URL safe", encoded)
+			}
+			decoded, err := decodePageToken(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded != tt.token {
+				t.Errorf("round trip gave %+v, want %+v", decoded, tt.token)
+			}
+		})
+	}
+}
+
+func TestListCollectionPages(t *testing.T) {
+	baseURL := New(Options{}).Start(t)
+	headers := map[string]string{"Accept": "application/protobuf", "X-Seed": "7"}
+
+	seen := map[string]bool{}
+	var total int32
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 100 {
+			t.Fatal("paging doesn't end")
+		}
+		resp, body := request(t, "GET", baseURL+"/outlets/outlet-001/news?page_size=5&page_token="+url.QueryEscape(token), "", headers)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("page %d: status %d: %s", pages+1, resp.StatusCode, body)
+		}
+		page := &pb.ListNewsResponse{}
+		decode(t, body, page)
+		if len(page.News) > 5 {
+			t.Fatalf("page %d has %d records, want at most 5", pages+1, len(page.News))
+		}
+		for _, news := range page.News {
+			if seen[news.NewsId] {
+				t.Errorf("%s is listed twice", news.NewsId)
+			}
+			seen[news.NewsId] = true
+		}
+		total = page.TotalSize
+		if token = page.NextPageToken; token == "" {
+			break
+		}
+	}
+	if len(seen) != int(total) {
+		t.Errorf("paged through %d records, total_size is %d", len(seen), total)
+	}
+}
+
+func TestListCollectionInvalidPageToken(t *testing.T) {
+	baseURL := New(Options{}).Start(t)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "not base64", query: "page_token=%25%25%25"},
+		{name: "not JSON", query: "page_token=" + url.QueryEscape("bm9wZQ")},
+		{name: "other sort", query: "sort=id&page_token=" + url.QueryEscape(encodePageToken(pageToken{Sort: "-date", ID: "news-001"}))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := request(t, "GET", baseURL+"/outlets/outlet-001/news?"+tt.query, "", nil)
+			if resp.StatusCode != http.StatusBadRequest || strings.TrimSpace(string(body)) != "Invalid page_token" {
+				t.Errorf("status %d, body %q; want 400 Invalid page_token", resp.StatusCode, body)
+			}
+		})
+	}
+}
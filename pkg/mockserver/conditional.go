@@ -0,0 +1,67 @@
+package mockserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"srv-eazle-advise-mock/pkg/cache"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+)
+
+// newEntry pairs an encoded response with its validators: an ETag over the
+// encoded bytes and the latest UpdatedAt of its outlets as Last-Modified
+func newEntry(data []byte, outlets []*pb.OutletDetails) cache.Entry {
+	sum := sha256.Sum256(data)
+	entry := cache.Entry{Data: data, ETag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+	for _, outlet := range outlets {
+		if outlet.UpdatedAt == nil {
+			continue
+		}
+		if updated := outlet.UpdatedAt.AsTime(); updated.After(entry.LastModified) {
+			entry.LastModified = updated
+		}
+	}
+	return entry
+}
+
+// setValidators sets the ETag and Last-Modified headers of entry
+func setValidators(w http.ResponseWriter, entry cache.Entry) {
+	w.Header().Set("ETag", entry.ETag)
+	if !entry.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", entry.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified reports whether a GET or HEAD already holds entry according to
+// If-None-Match or, without it, If-Modified-Since
+func notModified(r *http.Request, entry cache.Entry) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, entry.ETag)
+	}
+	if entry.LastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// Last-Modified only has second precision
+	return !entry.LastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches compares an If-None-Match list weakly against etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package mockserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"srv-eazle-advise-mock/pkg/fieldmask"
+	"srv-eazle-advise-mock/pkg/mock"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// requestMask reads the field mask of a response from the fields or
+// read_mask query parameters, comma separated paths relative to desc
+func requestMask(r *http.Request, desc protoreflect.MessageDescriptor) (*fieldmask.Mask, error) {
+	query := r.URL.Query()
+	var paths []string
+	for _, value := range append(query["fields"], query["read_mask"]...) {
+		paths = append(paths, strings.Split(value, ",")...)
+	}
+	mask, err := fieldmask.Parse(desc, paths)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid field mask: %v", err)
+	}
+	return mask, nil
+}
+
+// maskSettings skips generating the sub-collections of an outlet that mask
+// drops. Visits and orders are kept for the statistics computed from them.
+func maskSettings(settings mock.MockSettings, mask *fieldmask.Mask) mock.MockSettings {
+	if mask == nil {
+		return settings
+	}
+	statistics := mask.Includes("statistics")
+	if !mask.Includes("visit_history") && !statistics {
+		settings.AverageVisitHistory = 0
+	}
+	if !mask.Includes("order_history") && !statistics {
+		settings.AverageNumberOfOrders = 0
+	}
+	if !mask.Includes("statistics.top_products") {
+		settings.AverageTopProductsInStatistics = 0
+	}
+	if !mask.Includes("outlets_nearby") {
+		settings.AverageOutletsNearby = 0
+	}
+	if !mask.Includes("notes") {
+		settings.AverageNotesList = 0
+	}
+	if !mask.Includes("asset_list") {
+		settings.AverageAssetList = 0
+	}
+	if !mask.Includes("checklist") {
+		settings.AverageChecklist = 0
+	}
+	if !mask.Includes("news") {
+		settings.AverageNews = 0
+	}
+	return settings
+}
@@ -0,0 +1,69 @@
+package mockserver
+
+import (
+	"context"
+	"runtime"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+)
+
+// DefaultMaxOutlets caps X-Outlet-Num unless Options sets another limit
+const DefaultMaxOutlets = 10000
+
+type generateJob struct {
+	index  int
+	result chan *pb.OutletDetails
+}
+
+// generateOutlets generates count outlets on a pool of GOMAXPROCS workers and
+// passes them to emit in index order. At most twice the pool size is generated
+// ahead of emit, and generation stops when ctx is cancelled or emit fails.
+func generateOutlets(ctx context.Context, count int, generate func(index int) *pb.OutletDetails, emit func(*pb.OutletDetails) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := min(runtime.GOMAXPROCS(0), count)
+	jobs := make(chan generateJob)
+	// Results are queued in index order, the queue size bounds the work in flight
+	pending := make(chan chan *pb.OutletDetails, 2*workers)
+
+	for range workers {
+		go func() {
+			for job := range jobs {
+				if ctx.Err() == nil {
+					job.result <- generate(job.index)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		for i := range count {
+			job := generateJob{index: i, result: make(chan *pb.OutletDetails, 1)}
+			select {
+			case pending <- job.result:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for result := range pending {
+		select {
+		case outlet := <-result:
+			if err := emit(outlet); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return ctx.Err()
+}
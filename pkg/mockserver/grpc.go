@@ -0,0 +1,202 @@
+package mockserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"srv-eazle-advise-mock/pkg/fieldmask"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/latency"
+	"srv-eazle-advise-mock/pkg/mock"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// outletService serves OutletService over gRPC from the same settings,
+// fixtures and stateful universe as the HTTP API
+type outletService struct {
+	pb.UnimplementedOutletServiceServer
+	server *Server
+}
+
+// RegisterGRPC adds OutletService to a gRPC server
+func (s *Server) RegisterGRPC(registrar grpc.ServiceRegistrar) {
+	pb.RegisterOutletServiceServer(registrar, &outletService{server: s})
+}
+
+// StartGRPC serves OutletService on a local port until the test ends and returns its address
+func (s *Server) StartGRPC(t testing.TB) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	s.RegisterGRPC(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// streamFault ends a stream with an error after a number of messages, set
+// with the x-fault-code, x-fault-after and x-fault-message metadata
+type streamFault struct {
+	code    codes.Code
+	after   int
+	message string
+}
+
+// StreamOutlets sends outlets one message at a time. Metadata mirrors the HTTP
+// headers: x-delay-ms waits between messages and x-fault-code fails the stream.
+func (o *outletService) StreamOutlets(req *pb.StreamOutletsRequest, stream grpc.ServerStreamingServer[pb.OutletDetails]) error {
+	s := o.server
+	ctx := stream.Context()
+	md, _ := metadata.FromIncomingContext(ctx)
+	if !s.validateMetadataKey(md) {
+		return status.Error(codes.Unauthenticated, "Invalid secret key")
+	}
+
+	delay, fail, err := streamOptions(md)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	mask, err := fieldmask.Parse((&pb.OutletDetails{}).ProtoReflect().Descriptor(), req.GetReadMask().GetPaths())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid read_mask: %v", err)
+	}
+
+	sent := 0
+	send := func(outlet *pb.OutletDetails) error {
+		if fail != nil && sent == fail.after {
+			return status.Error(fail.code, fail.message)
+		}
+		if sent > 0 && !latency.Sleep(ctx, delay) {
+			return ctx.Err()
+		}
+		mask.Prune(outlet)
+		if err := stream.Send(outlet); err != nil {
+			return err
+		}
+		sent++
+		return nil
+	}
+
+	err = o.streamOutlets(ctx, req, maskSettings(o.server.Settings(), mask), send)
+	if err == nil && fail != nil && sent == fail.after {
+		// The fault fires after the last message too, like a stream cut before its trailer
+		err = status.Error(fail.code, fail.message)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return err
+}
+
+func (o *outletService) streamOutlets(ctx context.Context, req *pb.StreamOutletsRequest, settings mock.MockSettings, send func(*pb.OutletDetails) error) error {
+	s := o.server
+
+	// In stateful mode outlets come from the materialized universe
+	if s.store != nil {
+		outlets := s.store.List()
+		if req.OutletId != "" {
+			outlet, ok := s.store.Get(req.OutletId)
+			if !ok {
+				return status.Error(codes.NotFound, "Outlet not found")
+			}
+			outlets = []*pb.OutletDetails{outlet}
+		}
+		for _, outlet := range outlets {
+			if err := send(outlet); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	count := int(req.OutletCount)
+	if count == 0 {
+		count = 100
+	}
+	if count < 0 {
+		return status.Error(codes.InvalidArgument, "Invalid outlet_count")
+	}
+	if count > s.maxOutlets {
+		return status.Errorf(codes.ResourceExhausted, "outlet_count exceeds the maximum of %d outlets", s.maxOutlets)
+	}
+	outletID := req.OutletId
+	if outletID == "" {
+		outletID = "outlet-001"
+	}
+	if req.Locale != "" {
+		settings.Locale = req.Locale
+	}
+
+	// Fixtures come first and count towards outlet_count
+	fixtureCount := min(len(s.fixtures.Outlets), count)
+	for _, fixture := range s.fixtures.Outlets[:fixtureCount] {
+		if err := send(mock.CompleteOutlet(proto.Clone(fixture).(*pb.OutletDetails), settings)); err != nil {
+			return err
+		}
+	}
+
+	return generateOutlets(ctx, count-fixtureCount,
+		func(int) *pb.OutletDetails { return mock.GenerateMockedOutlet(outletID, settings) },
+		send)
+}
+
+// validateMetadataKey checks the secret key like validateSecretKey does for HTTP headers
+func (s *Server) validateMetadataKey(md metadata.MD) bool {
+	for _, key := range md.Get("x-api-key") {
+		if key == s.secretKey {
+			return true
+		}
+	}
+	for _, auth := range md.Get("authorization") {
+		if auth == "Bearer "+s.secretKey {
+			return true
+		}
+	}
+	return false
+}
+
+// streamOptions reads the delay between messages and the stream fault from metadata
+func streamOptions(md metadata.MD) (time.Duration, *streamFault, error) {
+	var delay time.Duration
+	if values := md.Get("x-delay-ms"); len(values) > 0 {
+		delayMs, err := strconv.Atoi(values[0])
+		if err != nil || delayMs < 0 {
+			return 0, nil, errors.New("Invalid x-delay-ms metadata")
+		}
+		delay = time.Duration(delayMs) * time.Millisecond
+	}
+
+	values := md.Get("x-fault-code")
+	if len(values) == 0 {
+		return delay, nil, nil
+	}
+	fail := &streamFault{}
+	if err := fail.code.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(values[0])))); err != nil || fail.code == codes.OK {
+		return 0, nil, errors.New("Invalid x-fault-code metadata, use a code name like UNAVAILABLE")
+	}
+	if values := md.Get("x-fault-after"); len(values) > 0 {
+		after, err := strconv.Atoi(values[0])
+		if err != nil || after < 0 {
+			return 0, nil, errors.New("Invalid x-fault-after metadata")
+		}
+		fail.after = after
+	}
+	fail.message = "Injected fault"
+	if values := md.Get("x-fault-message"); len(values) > 0 {
+		fail.message = values[0]
+	}
+	return delay, fail, nil
+}
@@ -0,0 +1,92 @@
+package mockserver
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+
+	"srv-eazle-advise-mock/pkg/fault"
+	"srv-eazle-advise-mock/pkg/idempotency"
+)
+
+// idempotent runs a write at most once per Idempotency-Key and API key.
+// Retries with the same key and body get the stored response replayed with
+// Idempotent-Replayed: true, another request under the key gets a 422.
+// A networkFault breaks the delivery after the result is stored, like a
+// response lost on its way back, so the retry replays it.
+func (s *Server) idempotent(w http.ResponseWriter, r *http.Request, networkFault fault.Rule, write http.HandlerFunc) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading body", http.StatusBadRequest)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		deliver(w, r, record(write, r), networkFault)
+		return
+	}
+
+	key = apiKey(r) + "|" + key
+	outcome, stored := s.idempotency.Begin(key, idempotency.Fingerprint(r.Method, r.URL.Path, body))
+	switch outcome {
+	case idempotency.Mismatch:
+		http.Error(w, "Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity)
+	case idempotency.InProgress:
+		http.Error(w, "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+	case idempotency.Replay:
+		w.Header().Set("Idempotent-Replayed", "true")
+		deliver(w, r, stored, networkFault)
+	default:
+		response := record(write, r)
+		// Server errors are not stored so the retry gets another chance
+		if response.Status >= http.StatusInternalServerError {
+			s.idempotency.Abandon(key)
+		} else {
+			s.idempotency.Complete(key, response)
+		}
+		deliver(w, r, response, networkFault)
+	}
+}
+
+// record runs write against a recorder and returns its response
+func record(write http.HandlerFunc, r *http.Request) idempotency.Response {
+	recorder := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+	write(recorder, r)
+	return idempotency.Response{Status: recorder.status, Header: recorder.header, Body: recorder.body.Bytes()}
+}
+
+// deliver sends a recorded response, broken in the way networkFault
+// describes if it has a mode
+func deliver(w http.ResponseWriter, r *http.Request, response idempotency.Response, networkFault fault.Rule) {
+	for name, values := range response.Header {
+		w.Header()[name] = values
+	}
+	if networkFault.Mode != "" {
+		fault.WriteBody(w, r, networkFault, response.Status, response.Body)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(response.Body)))
+	w.WriteHeader(response.Status)
+	w.Write(response.Body)
+}
+
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) Header() http.Header {
+	return rr.header
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	rr.status = status
+}
+
+func (rr *responseRecorder) Write(data []byte) (int, error) {
+	return rr.body.Write(data)
+}
@@ -0,0 +1,57 @@
+package mockserver
+
+import (
+	"net/http"
+	"testing"
+
+	"srv-eazle-advise-mock/pkg/idempotency"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	baseURL := New(Options{Stateful: true, Outlets: 1}).Start(t)
+	path := baseURL + "/outlets/outlet-001/notes"
+
+	tests := []struct {
+		name         string
+		key          string
+		body         string
+		headers      map[string]string
+		wantStatus   int
+		wantReplayed bool
+	}{
+		{name: "first request", key: "a", body: `{"title": "Restock"}`, wantStatus: http.StatusCreated},
+		{name: "retry", key: "a", body: `{"title": "Restock"}`, wantStatus: http.StatusCreated, wantReplayed: true},
+		{name: "other body under the key", key: "a", body: `{"title": "Prices"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "other body under the key as bearer token", key: "a", body: `{"title": "Prices"}`, headers: map[string]string{"X-API-Key": "", "Authorization": "Bearer " + DefaultSecretKey}, wantStatus: http.StatusUnprocessableEntity},
+		{name: "other key", key: "b", body: `{"title": "Prices"}`, wantStatus: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{"Idempotency-Key": tt.key}
+			for name, value := range tt.headers {
+				headers[name] = value
+			}
+			resp, body := request(t, "POST", path, tt.body, headers)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", resp.StatusCode, tt.wantStatus, body)
+			}
+			if replayed := resp.Header.Get("Idempotent-Replayed") == "true"; replayed != tt.wantReplayed {
+				t.Errorf("replayed %v, want %v", replayed, tt.wantReplayed)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyInProgress(t *testing.T) {
+	server := New(Options{Stateful: true, Outlets: 1})
+	baseURL := server.Start(t)
+	body := `{"title": "Restock"}`
+
+	// Reserve the key the way a request still being processed does
+	server.idempotency.Begin(DefaultSecretKey+"|a", idempotency.Fingerprint("POST", "/outlets/outlet-001/notes", []byte(body)))
+
+	resp, _ := request(t, "POST", baseURL+"/outlets/outlet-001/notes", body, map[string]string{"Idempotency-Key": "a"})
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("retry while in progress: status %d, want 409", resp.StatusCode)
+	}
+}
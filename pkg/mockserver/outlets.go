@@ -0,0 +1,373 @@
+package mockserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"srv-eazle-advise-mock/pkg/cache"
+	"srv-eazle-advise-mock/pkg/fault"
+	"srv-eazle-advise-mock/pkg/fieldmask"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/latency"
+	"srv-eazle-advise-mock/pkg/mock"
+	"srv-eazle-advise-mock/pkg/scenario"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
+}
+
+// handleOutletDetails serves /outlets, generated per request or from the
+// universe in stateful mode
+func (s *Server) handleOutletDetails(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	// Only the fields the client asked for are sent
+	mask, err := requestMask(r, (&pb.OutletDetails{}).ProtoReflect().Descriptor())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// In stateful mode outlets come from the materialized universe
+	if s.store != nil {
+		s.handleStatefulOutlets(w, r, networkFault, mask)
+		return
+	}
+
+	// Get outlet ID from query parameter
+	outletID := r.URL.Query().Get("outlet_id")
+	if outletID == "" {
+		outletID = "outlet-001" // Default outlet
+	}
+
+	// Generate mock outlet data with configurable settings
+	settings := s.Settings()
+	if acceptLanguage := r.Header.Get("Accept-Language"); acceptLanguage != "" {
+		settings.Locale = mock.MatchLocale(acceptLanguage)
+	}
+
+	// If request body exists, try to decode custom settings
+	if r.Body != nil {
+		decoder := json.NewDecoder(r.Body)
+		var customSettings mock.MockSettingsOptional
+		if err := decoder.Decode(&customSettings); err == nil {
+			if customSettings.AverageNotesList != nil {
+				settings.AverageNotesList = *customSettings.AverageNotesList
+			}
+			if customSettings.AverageVisitHistory != nil {
+				settings.AverageVisitHistory = *customSettings.AverageVisitHistory
+			}
+			if customSettings.AverageNumberOfOrders != nil {
+				settings.AverageNumberOfOrders = *customSettings.AverageNumberOfOrders
+			}
+			if customSettings.AverageOrderItemsPerOrder != nil {
+				settings.AverageOrderItemsPerOrder = *customSettings.AverageOrderItemsPerOrder
+			}
+			if customSettings.AverageTopProductsInStatistics != nil {
+				settings.AverageTopProductsInStatistics = *customSettings.AverageTopProductsInStatistics
+			}
+			if customSettings.AverageOutletsNearby != nil {
+				settings.AverageOutletsNearby = *customSettings.AverageOutletsNearby
+			}
+			if customSettings.AverageAssetList != nil {
+				settings.AverageAssetList = *customSettings.AverageAssetList
+			}
+			if customSettings.AverageChecklist != nil {
+				settings.AverageChecklist = *customSettings.AverageChecklist
+			}
+			if customSettings.AverageNews != nil {
+				settings.AverageNews = *customSettings.AverageNews
+			}
+			if customSettings.Locale != nil {
+				settings.Locale = *customSettings.Locale
+			}
+		}
+	}
+
+	numberOfOutletsString := r.Header.Get("X-Outlet-Num")
+	if numberOfOutletsString == "" {
+		numberOfOutletsString = "100"
+	}
+	numberOfOutlets, err := strconv.Atoi(numberOfOutletsString)
+	if err != nil || numberOfOutlets < 0 {
+		http.Error(w, "Invalid X-Outlet-Num header", http.StatusBadRequest)
+		return
+	}
+	if numberOfOutlets > s.maxOutlets {
+		http.Error(w, fmt.Sprintf("X-Outlet-Num exceeds the maximum of %d outlets", s.maxOutlets), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// With a seed the same request always generates the same outlets, dated
+	// relative to the start of the day so they stay the same until midnight
+	generate := func(outlet *pb.OutletDetails, index int) *pb.OutletDetails {
+		return mock.CompleteOutlet(outlet, settings)
+	}
+	var cacheKey string
+	if seedHeader := r.Header.Get("X-Seed"); seedHeader != "" {
+		seed, err := strconv.ParseInt(seedHeader, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid X-Seed header", http.StatusBadRequest)
+			return
+		}
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		generate = func(outlet *pb.OutletDetails, index int) *pb.OutletDetails {
+			return mock.CompleteSeededOutlet(outlet, settings, seed+int64(index), today)
+		}
+		cacheKey = responseCacheKey(r, outletID, numberOfOutlets, seed, today, settings, mask)
+	} else {
+		// Seeded outlets are generated in full, so a masked response is a part of
+		// the unmasked one. Otherwise dropped sub-collections aren't generated at all.
+		settings = maskSettings(settings, mask)
+	}
+
+	// Outlets are sent as they are generated when the client asked for a stream
+	out := newOutletWriter(w, r, networkFault, mask)
+
+	// Seeded responses are cached, streams are always generated
+	if buffered, ok := out.(*bufferedWriter); ok && cacheKey != "" && s.cache != nil {
+		if entry, ok := s.cache.Get(cacheKey); ok {
+			w.Header().Set("X-Cache", "HIT")
+			writeEncoded(w, r, encodedContentType(r), entry, networkFault)
+			return
+		}
+		w.Header().Set("X-Cache", "MISS")
+		buffered.encoded = func(entry cache.Entry) { s.cache.Put(cacheKey, entry) }
+	}
+
+	// Fixtures come first and count towards X-Outlet-Num
+	fixtureCount := min(len(s.fixtures.Outlets), numberOfOutlets)
+	for i, fixture := range s.fixtures.Outlets[:fixtureCount] {
+		if err := out.write(generate(proto.Clone(fixture).(*pb.OutletDetails), i)); err != nil {
+			return
+		}
+	}
+
+	err = generateOutlets(r.Context(), numberOfOutlets-fixtureCount,
+		func(index int) *pb.OutletDetails {
+			return generate(&pb.OutletDetails{OutletId: outletID}, fixtureCount+index)
+		},
+		out.write)
+	if err != nil {
+		// The client is gone, nobody is left to answer
+		return
+	}
+
+	out.finish()
+}
+
+// responseCacheKey identifies everything an encoded seeded response depends on
+func responseCacheKey(r *http.Request, outletID string, count int, seed int64, today time.Time, settings mock.MockSettings, mask *fieldmask.Mask) string {
+	settingsJSON, _ := json.Marshal(settings)
+	return fmt.Sprintf("%s|%s|%d|%d|%s|%s|%s", encodedContentType(r), outletID, count, seed, today.Format(time.DateOnly), settingsJSON, mask)
+}
+
+func (s *Server) handleStatefulOutlets(w http.ResponseWriter, r *http.Request, networkFault fault.Rule, mask *fieldmask.Mask) {
+	outlets := []*pb.OutletDetails{}
+
+	if outletID := r.URL.Query().Get("outlet_id"); outletID != "" {
+		outlet, ok := s.store.Get(outletID)
+		if !ok {
+			http.Error(w, "Outlet not found", http.StatusNotFound)
+			return
+		}
+		outlets = append(outlets, outlet)
+	} else {
+		outlets = s.store.List()
+	}
+
+	out := newOutletWriter(w, r, networkFault, mask)
+	for _, outlet := range outlets {
+		if err := out.write(outlet); err != nil {
+			return
+		}
+	}
+	out.finish()
+}
+
+// encodedContentType picks protobuf or JSON for a buffered response from the Accept header
+func encodedContentType(r *http.Request) string {
+	if r.Header.Get("Accept") == "application/protobuf" {
+		return "application/protobuf"
+	}
+	return "application/json"
+}
+
+// encodeResponse marshals a response in the format encodedContentType picks
+func encodeResponse(r *http.Request, response proto.Message) ([]byte, error) {
+	if encodedContentType(r) == "application/protobuf" {
+		return proto.Marshal(response)
+	}
+	return protojson.Marshal(response)
+}
+
+// writeEncoded sends an encoded response with its validators, or a 304 when
+// the client already has it. The body is broken in the way networkFault
+// describes if it has a mode.
+func writeEncoded(w http.ResponseWriter, r *http.Request, contentType string, entry cache.Entry, networkFault fault.Rule) {
+	setValidators(w, entry)
+	if notModified(r, entry) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if networkFault.Mode != "" {
+		fault.WriteBody(w, r, networkFault, http.StatusOK, entry.Data)
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(entry.Data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(entry.Data)
+}
+
+func (s *Server) validateSecretKey(r *http.Request) bool {
+	authHeader := r.Header.Get("Authorization")
+	apiKey := r.Header.Get("X-API-Key")
+
+	// Check both Authorization header and X-API-Key header
+	return authHeader == "Bearer "+s.secretKey || apiKey == s.secretKey
+}
+
+// apiKey returns the credential checked by validateSecretKey
+func apiKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// apiHandler serves an API route once apiRoute let the request through.
+// networkFault is applied once the body is written, see writeEncoded.
+type apiHandler func(w http.ResponseWriter, r *http.Request, networkFault fault.Rule)
+
+// apiRoute runs what every API request goes through before its handler:
+// the secret key, the rate limit, the scenario timeline, the delay and
+// fault injection
+func (s *Server) apiRoute(next apiHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Check secret key
+		if !s.validateSecretKey(r) {
+			http.Error(w, "Unauthorized - Invalid secret key", http.StatusUnauthorized)
+			return
+		}
+
+		// Throttle the API key like the real backend does
+		if s.handleRateLimit(w, r) {
+			return
+		}
+
+		// Move the client along its scenario timeline, if one applies
+		step, inScenario, err := s.scenarios.Advance(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if inScenario {
+			w.Header().Set("X-Scenario-Step", fmt.Sprintf("%s/%d", step.Scenario, step.Index+1))
+		}
+
+		// Handle delay if specified
+		w, err = s.handleDelay(w, r, step)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Fail the request if a fault is configured for it, network faults
+		// only kick in once the body is written
+		networkFault, failed := s.handleFault(w, r, step)
+		if failed {
+			return
+		}
+		if networkFault.Mode == fault.ModeStall {
+			// A stall holds the response until the client or the server gives up
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			defer context.AfterFunc(s.stalls, cancel)()
+			r = r.WithContext(ctx)
+		}
+
+		next(w, r, networkFault)
+	}
+}
+
+// handleRateLimit takes a token from the API key's bucket, sets the RateLimit-*
+// headers and writes a 429 when the bucket is empty, reporting whether it did
+func (s *Server) handleRateLimit(w http.ResponseWriter, r *http.Request) bool {
+	state, allowed, limited := s.limiter.Take(apiKey(r), r.URL.Path)
+	if !limited {
+		return false
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(state.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(state.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(state.ResetSeconds))
+	if allowed {
+		return false
+	}
+	fault.WriteError(w, fault.Rule{Status: http.StatusTooManyRequests, RetryAfter: state.RetryAfter})
+	return true
+}
+
+// handleFault writes an injected error response and reports whether it did.
+// Network faults are returned instead, they are applied once the body is written.
+// A fault of the current scenario step wins over the configured ones.
+func (s *Server) handleFault(w http.ResponseWriter, r *http.Request, step scenario.Active) (fault.Rule, bool) {
+	rule, ok, err := s.faults.Pick(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return fault.Rule{}, true
+	}
+	if step.Step.Fault != nil {
+		rule, ok = *step.Step.Fault, step.Step.Fault.Fires()
+	}
+	if !ok {
+		return fault.Rule{}, false
+	}
+	if rule.Mode != "" {
+		return rule, false
+	}
+	fault.WriteError(w, rule)
+	return fault.Rule{}, true
+}
+
+// handleDelay waits for X-Delay-Ms plus the delays of the scenario step and
+// the request's latency profile, and returns the writer to use for the body:
+// throttled when the profile limits bandwidth
+func (s *Server) handleDelay(w http.ResponseWriter, r *http.Request, step scenario.Active) (http.ResponseWriter, error) {
+	delay := step.Step.Delay(step.Elapsed)
+	delayHeader := r.Header.Get("X-Delay-Ms")
+	if delayHeader != "" {
+		if delayMs, err := strconv.Atoi(delayHeader); err == nil && delayMs > 0 {
+			delay += time.Duration(delayMs) * time.Millisecond
+		}
+	}
+
+	profile, ok, err := s.latency.Select(r)
+	if err != nil {
+		return w, err
+	}
+	if name := step.Step.LatencyProfile; name != "" {
+		if profile, ok = s.latency.Profile(name); !ok {
+			return w, fmt.Errorf("Unknown latency profile %q in scenario %q", name, step.Scenario)
+		}
+	}
+	if ok {
+		delay += profile.Delay()
+		w = latency.Throttle(r.Context(), w, profile.BandwidthKbps)
+	}
+
+	latency.Sleep(r.Context(), delay)
+	return w, nil
+}
@@ -0,0 +1,330 @@
+package mockserver
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"srv-eazle-advise-mock/pkg/cache"
+	"srv-eazle-advise-mock/pkg/conflict"
+	"srv-eazle-advise-mock/pkg/fault"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// resource is a mutable record type of the stateful universe, served with its
+// version as ETag and written with optimistic concurrency
+type resource struct {
+	recordType   pb.SyncRecordType
+	conflictType string // empty when conflicts can't be forced on it
+	name         string
+}
+
+var (
+	outletResource        = resource{pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET, conflict.TypeOutlet, "Outlet"}
+	noteResource          = resource{pb.SyncRecordType_SYNC_RECORD_TYPE_NOTE, conflict.TypeNote, "Note"}
+	checklistItemResource = resource{pb.SyncRecordType_SYNC_RECORD_TYPE_CHECKLIST_ITEM, conflict.TypeChecklistItem, "Checklist item"}
+	visitResource         = resource{pb.SyncRecordType_SYNC_RECORD_TYPE_VISIT, "", "Visit"}
+	orderResource         = resource{pb.SyncRecordType_SYNC_RECORD_TYPE_ORDER, "", "Order"}
+)
+
+func (s *Server) handleOutlet(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveResource(w, r, outletResource, r.PathValue("outletID"), "", networkFault)
+}
+
+func (s *Server) handleNote(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveResource(w, r, noteResource, r.PathValue("outletID"), r.PathValue("recordID"), networkFault)
+}
+
+func (s *Server) handleVisit(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveResource(w, r, visitResource, r.PathValue("outletID"), r.PathValue("recordID"), networkFault)
+}
+
+func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveResource(w, r, orderResource, r.PathValue("outletID"), r.PathValue("recordID"), networkFault)
+}
+
+func (s *Server) handleChecklistItem(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	s.serveResource(w, r, checklistItemResource, r.PathValue("outletID"), r.PathValue("recordID"), networkFault)
+}
+
+// createRecord adds a record to an outlet, with a generated ID unless the
+// body has one, and answers with the record and its Location
+func (s *Server) createRecord(w http.ResponseWriter, r *http.Request, res resource, outletID string) {
+	record, err := decodeRecord(r, res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id := recordIDField(record)
+	if *id == "" {
+		*id = fmt.Sprintf("%s-%08x", strings.ToLower(res.name), rand.Uint32())
+	}
+
+	now := timestamppb.Now()
+	switch record := record.(type) {
+	case *pb.Note:
+		if record.CreatedAt == nil {
+			record.CreatedAt = now
+		}
+		record.UpdatedAt = now
+	case *pb.Visit:
+		if record.VisitDate == nil {
+			record.VisitDate = now
+		}
+		if record.VisitStatus == pb.VisitStatus_VISIT_STATUS_UNSPECIFIED {
+			record.VisitStatus = pb.VisitStatus_VISIT_STATUS_COMPLETED
+		}
+	case *pb.Order:
+		if record.OrderDate == nil {
+			record.OrderDate = now
+		}
+		if record.OrderNumber == "" {
+			record.OrderNumber = fmt.Sprintf("ORD-%d", now.AsTime().UnixMilli())
+		}
+		if record.Status == pb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+			record.Status = pb.OrderStatus_ORDER_STATUS_PENDING
+		}
+	}
+
+	s.writes.Lock()
+	defer s.writes.Unlock()
+
+	if _, ok := s.store.Get(outletID); !ok {
+		http.Error(w, "Outlet not found", http.StatusNotFound)
+		return
+	}
+	if _, ok := s.store.Record(outletID, res.recordType, *id); ok {
+		http.Error(w, fmt.Sprintf("%s %s already exists", res.name, *id), http.StatusConflict)
+		return
+	}
+	if err := s.store.PutRecord(outletID, record); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	current, version, _ := s.store.Versioned(res.recordType, outletID, *id)
+	w.Header().Set("Location", r.URL.Path+"/"+*id)
+	s.writeVersioned(w, r, http.StatusCreated, current, version, fault.Rule{})
+}
+
+// serveResource reads, replaces or deletes a single outlet or record. Writes
+// need an If-Match with the current ETag.
+func (s *Server) serveResource(w http.ResponseWriter, r *http.Request, res resource, outletID, recordID string, networkFault fault.Rule) {
+	if s.store == nil {
+		http.Error(w, "Records are only available in stateful mode", http.StatusConflict)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		current, version, ok := s.store.Versioned(res.recordType, outletID, recordID)
+		if !ok {
+			http.Error(w, res.name+" not found", http.StatusNotFound)
+			return
+		}
+		mask, err := requestMask(r, current.ProtoReflect().Descriptor())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mask.Prune(current)
+		s.writeVersioned(w, r, http.StatusOK, current, version, networkFault)
+	case http.MethodPut, http.MethodDelete:
+		s.writeResource(w, r, res, outletID, recordID, networkFault)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeResource applies a PUT or DELETE if its If-Match names the current
+// version. A stale version gets a 412 and a forced conflict the status of its
+// rule, both with the current record and its ETag.
+func (s *Server) writeResource(w http.ResponseWriter, r *http.Request, res resource, outletID, recordID string, networkFault fault.Rule) {
+	var record proto.Message
+	if r.Method == http.MethodPut {
+		var err error
+		if record, err = decodeResource(r, res, outletID, recordID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// The version check and the write it guards happen as one
+	s.writes.Lock()
+	defer s.writes.Unlock()
+
+	current, version, ok := s.store.Versioned(res.recordType, outletID, recordID)
+	if !ok {
+		http.Error(w, res.name+" not found", http.StatusNotFound)
+		return
+	}
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		http.Error(w, "If-Match with the current ETag is required", http.StatusPreconditionRequired)
+		return
+	}
+	if rule, forced := s.conflicts.Match(res.conflictType, outletID, recordID); forced {
+		// Another client got there first
+		current, version = s.touchResource(res, outletID, recordID, current)
+		s.writeVersioned(w, r, rule.Status, current, version, networkFault)
+		return
+	}
+	if !etagListMatches(ifMatch, versionETag(version)) {
+		s.writeVersioned(w, r, http.StatusPreconditionFailed, current, version, networkFault)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if res.recordType == pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET {
+			s.store.Delete(outletID)
+		} else {
+			s.store.DeleteRecord(outletID, res.recordType, recordID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if outlet, ok := record.(*pb.OutletDetails); ok {
+		s.store.Put(outlet)
+	} else if err := s.store.PutRecord(outletID, record); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	current, version, _ = s.store.Versioned(res.recordType, outletID, recordID)
+	s.writeVersioned(w, r, http.StatusOK, current, version, networkFault)
+}
+
+// decodeResource reads the new state of a record from a PUT, taking its IDs from the path
+func decodeResource(r *http.Request, res resource, outletID, recordID string) (proto.Message, error) {
+	record, err := decodeRecord(r, res)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.recordType == pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET {
+		recordID = outletID
+	}
+	id := recordIDField(record)
+	if *id != "" && *id != recordID {
+		return nil, fmt.Errorf("%s ID %s does not match the path", res.name, *id)
+	}
+	*id = recordID
+
+	now := timestamppb.Now()
+	switch record := record.(type) {
+	case *pb.OutletDetails:
+		record.UpdatedAt = now
+	case *pb.Note:
+		record.UpdatedAt = now
+	}
+	return record, nil
+}
+
+// decodeRecord reads a record of the resource's type from a protobuf or JSON body
+func decodeRecord(r *http.Request, res resource) (proto.Message, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading body")
+	}
+
+	var record proto.Message
+	switch res.recordType {
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET:
+		record = &pb.OutletDetails{}
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_NOTE:
+		record = &pb.Note{}
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_VISIT:
+		record = &pb.Visit{}
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_ORDER:
+		record = &pb.Order{}
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_CHECKLIST_ITEM:
+		record = &pb.ChecklistItem{}
+	}
+	if r.Header.Get("Content-Type") == "application/protobuf" {
+		err = proto.Unmarshal(body, record)
+	} else {
+		err = protojson.Unmarshal(body, record)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s: %v", strings.ToLower(res.name), err)
+	}
+	return record, nil
+}
+
+// recordIDField points at the ID of a record decoded by decodeRecord
+func recordIDField(record proto.Message) *string {
+	switch record := record.(type) {
+	case *pb.OutletDetails:
+		return &record.OutletId
+	case *pb.Note:
+		return &record.NoteId
+	case *pb.Visit:
+		return &record.VisitId
+	case *pb.Order:
+		return &record.OrderId
+	case *pb.ChecklistItem:
+		return &record.ItemId
+	}
+	return nil
+}
+
+// touchResource simulates a concurrent change to a record by bumping its
+// version, the caller holds s.writes
+func (s *Server) touchResource(res resource, outletID, recordID string, current proto.Message) (proto.Message, int64) {
+	now := timestamppb.Now()
+	switch current := current.(type) {
+	case *pb.OutletDetails:
+		current.UpdatedAt = now
+		s.store.Put(current)
+	case *pb.Note:
+		current.UpdatedAt = now
+		s.store.PutRecord(outletID, current)
+	default:
+		s.store.PutRecord(outletID, current)
+	}
+	current, version, _ := s.store.Versioned(res.recordType, outletID, recordID)
+	return current, version
+}
+
+// writeVersioned sends a record with its version as ETag, broken in the way
+// networkFault describes if it has a mode
+func (s *Server) writeVersioned(w http.ResponseWriter, r *http.Request, status int, record proto.Message, version int64, networkFault fault.Rule) {
+	data, err := encodeResponse(r, record)
+	if err != nil {
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+	entry := cache.Entry{Data: data, ETag: versionETag(version)}
+	if status == http.StatusOK {
+		writeEncoded(w, r, encodedContentType(r), entry, networkFault)
+		return
+	}
+	setValidators(w, entry)
+	w.Header().Set("Content-Type", encodedContentType(r))
+	if networkFault.Mode != "" {
+		fault.WriteBody(w, r, networkFault, status, data)
+		return
+	}
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func versionETag(version int64) string {
+	return fmt.Sprintf(`"v%d"`, version)
+}
+
+// etagListMatches compares an If-Match list strongly against etag
+func etagListMatches(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,460 @@
+package mockserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"srv-eazle-advise-mock/pkg/cache"
+	"srv-eazle-advise-mock/pkg/conflict"
+	"srv-eazle-advise-mock/pkg/fault"
+	"srv-eazle-advise-mock/pkg/fixtures"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/idempotency"
+	"srv-eazle-advise-mock/pkg/journal"
+	"srv-eazle-advise-mock/pkg/latency"
+	"srv-eazle-advise-mock/pkg/mock"
+	"srv-eazle-advise-mock/pkg/proxy"
+	"srv-eazle-advise-mock/pkg/ratelimit"
+	"srv-eazle-advise-mock/pkg/scenario"
+	"srv-eazle-advise-mock/pkg/store"
+	"srv-eazle-advise-mock/pkg/stub"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultSecretKey is accepted as X-API-Key or Bearer token unless Options sets another one
+const DefaultSecretKey = "eazle-secret-2025"
+
+// ErrNotStateful is returned by operations that need the stateful outlet universe
+var ErrNotStateful = errors.New("Snapshots are only available in stateful mode")
+
+// Options configures a Server, the zero value serves generated outlets with
+// the default settings
+type Options struct {
+	SecretKey string             // defaults to DefaultSecretKey
+	Settings  *mock.MockSettings // defaults for generated outlets, DefaultSettings(mock.DefaultLocale) when nil
+
+	// Fixtures are mixed into every response ahead of the generated outlets.
+	// Their products replace the product catalog of the generated outlets.
+	Fixtures *fixtures.Fixtures
+
+	// Stateful serves a persistent universe of Outlets outlets (default 100)
+	// instead of generating outlets per request. Universe is used as is when
+	// set, e.g. after restoring a snapshot.
+	Stateful     bool
+	Outlets      int
+	Universe     *store.Store
+	SnapshotPath string // default file of POST /__admin/snapshot
+
+	// ChangesPerMinute simulates background changes to the stateful universe
+	// for /sync/outlets to pick up, 0 leaves them off
+	ChangesPerMinute float64
+
+	// Seeded responses (X-Seed) are cached when either limit is set, 0 leaves a limit off
+	CacheEntries int
+	CacheBytes   int64
+
+	MaxOutlets  int          // largest accepted X-Outlet-Num, default DefaultMaxOutlets
+	JournalSize int          // requests kept for the verification API, default 1000
+	Upstream    *proxy.Proxy // forwards requests no stub mapping answers instead of generating them
+}
+
+// Server is the mock backend as an http.Handler, with typed methods for
+// everything the /__admin API can do
+type Server struct {
+	secretKey    string
+	snapshotPath string
+	maxOutlets   int
+
+	mu       sync.RWMutex
+	settings mock.MockSettings
+
+	fixtures *fixtures.Fixtures
+	store    *store.Store // only set in stateful mode
+	cache    *cache.Cache // only set when Options enable it
+	writes   sync.Mutex   // serializes version checks with the writes they guard
+
+	faults      *fault.Injector
+	latency     *latency.Registry
+	scenarios   *scenario.Engine
+	limiter     *ratelimit.Limiter
+	journal     *journal.Journal
+	stubs       *stub.Store
+	conflicts   *conflict.Registry
+	idempotency *idempotency.Store
+
+	handler        http.Handler
+	stopBackground func() // only set while background changes run
+	stalls         context.Context
+	endStalls      context.CancelFunc
+}
+
+// New creates a server, materializing the outlet universe in stateful mode
+func New(opts Options) *Server {
+	s := &Server{
+		secretKey:    opts.SecretKey,
+		snapshotPath: opts.SnapshotPath,
+		maxOutlets:   opts.MaxOutlets,
+		fixtures:     opts.Fixtures,
+		faults:       fault.NewInjector(),
+		latency:      latency.NewRegistry(),
+		scenarios:    scenario.NewEngine(),
+		limiter:      ratelimit.NewLimiter(),
+		conflicts:    conflict.NewRegistry(),
+		idempotency:  idempotency.New(10000, 24*time.Hour),
+	}
+	s.stalls, s.endStalls = context.WithCancel(context.Background())
+	if s.secretKey == "" {
+		s.secretKey = DefaultSecretKey
+	}
+	if s.maxOutlets == 0 {
+		s.maxOutlets = DefaultMaxOutlets
+	}
+	if opts.Settings != nil {
+		s.settings = *opts.Settings
+	} else {
+		s.settings = DefaultSettings(mock.DefaultLocale)
+	}
+	if s.fixtures == nil {
+		s.fixtures = &fixtures.Fixtures{}
+	}
+	if len(s.settings.Products) == 0 {
+		s.settings.Products = s.fixtures.Products
+	}
+	if opts.JournalSize == 0 {
+		opts.JournalSize = 1000
+	}
+	s.journal = journal.New(opts.JournalSize)
+	if opts.CacheEntries > 0 || opts.CacheBytes > 0 {
+		s.cache = cache.New(opts.CacheEntries, opts.CacheBytes)
+	}
+	s.stubs = stub.NewStore(s.Settings)
+
+	if opts.Stateful {
+		s.store = opts.Universe
+		if s.store == nil {
+			if opts.Outlets == 0 {
+				opts.Outlets = 100
+			}
+			s.store = s.materialize(opts.Outlets)
+		}
+		if opts.ChangesPerMinute > 0 {
+			s.stopBackground = s.startBackgroundChanges(opts.ChangesPerMinute)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/outlets", s.apiRoute(s.handleOutletDetails))
+	mux.HandleFunc("/outlets/{outletID}", s.apiRoute(s.handleOutlet))
+	mux.HandleFunc("/outlets/{outletID}/notes", s.apiRoute(s.handleNotes))
+	mux.HandleFunc("/outlets/{outletID}/notes/{recordID}", s.apiRoute(s.handleNote))
+	mux.HandleFunc("/outlets/{outletID}/visits", s.apiRoute(s.handleVisits))
+	mux.HandleFunc("/outlets/{outletID}/visits/{recordID}", s.apiRoute(s.handleVisit))
+	mux.HandleFunc("/outlets/{outletID}/orders", s.apiRoute(s.handleOrders))
+	mux.HandleFunc("/outlets/{outletID}/orders/{recordID}", s.apiRoute(s.handleOrder))
+	mux.HandleFunc("/outlets/{outletID}/assets", s.apiRoute(s.handleAssets))
+	mux.HandleFunc("/outlets/{outletID}/checklist", s.apiRoute(s.handleChecklist))
+	mux.HandleFunc("/outlets/{outletID}/checklist/{recordID}", s.apiRoute(s.handleChecklistItem))
+	mux.HandleFunc("/outlets/{outletID}/news", s.apiRoute(s.handleNews))
+	mux.HandleFunc("/sync/outlets", s.apiRoute(s.handleSyncOutlets))
+	mux.HandleFunc("/health", handleHealth)
+	mux.HandleFunc("/__admin/snapshot", s.handleSnapshot)
+	mux.HandleFunc("/__admin/faults", s.handleFaults)
+	mux.HandleFunc("/__admin/latency", s.handleLatency)
+	mux.HandleFunc("/__admin/scenarios", s.handleScenarios)
+	mux.HandleFunc("/__admin/scenarios/reset", s.handleScenariosReset)
+	mux.HandleFunc("/__admin/ratelimits", s.handleRateLimits)
+	mux.HandleFunc("/__admin/requests", s.handleRequests)
+	mux.HandleFunc("/__admin/requests/count", s.handleRequestsCount)
+	mux.HandleFunc("/__admin/mappings", s.handleMappings)
+	mux.HandleFunc("/__admin/cache", s.handleCache)
+	mux.HandleFunc("/__admin/conflicts", s.handleConflicts)
+	mux.HandleFunc("/__admin/idempotency", s.handleIdempotency)
+
+	// Stub mappings answer first, behind the same auth, rate limits, scenarios,
+	// latency and faults as the routes. Everything else falls through to the
+	// routes above or, in proxy mode, to the upstream.
+	var fallThrough http.Handler = mux
+	if opts.Upstream != nil {
+		fallThrough = opts.Upstream.Handler(mux)
+	}
+	s.handler = s.journal.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mapping, ok := s.stubs.Match(r)
+		if !ok {
+			fallThrough.ServeHTTP(w, r)
+			return
+		}
+		s.apiRoute(func(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+			s.stubs.Serve(w, r, mapping, networkFault)
+		})(w, r)
+	}), mux)
+	return s
+}
+
+// DefaultSettings are the settings generated outlets use unless a request overrides them
+func DefaultSettings(locale string) mock.MockSettings {
+	return mock.MockSettings{
+		AverageNotesList:               30,
+		AverageVisitHistory:            96,
+		AverageNumberOfOrders:          90,
+		AverageOrderItemsPerOrder:      20,
+		AverageTopProductsInStatistics: 6,
+		AverageOutletsNearby:           10,
+		AverageAssetList:               6,
+		AverageChecklist:               18,
+		AverageNews:                    22,
+		Locale:                         locale,
+	}
+}
+
+// materialize builds a universe of size outlets, fixtures first
+func (s *Server) materialize(size int) *store.Store {
+	universe := store.New()
+	settings := s.Settings()
+	for _, fixture := range s.fixtures.Outlets {
+		universe.Put(mock.CompleteOutlet(proto.Clone(fixture).(*pb.OutletDetails), settings))
+	}
+	// Synthetic outlets make up the rest of the universe, skipping IDs taken by fixtures
+	for i := 1; universe.Len() < size; i++ {
+		outletID := fmt.Sprintf("outlet-%03d", i)
+		if _, ok := universe.Get(outletID); ok {
+			continue
+		}
+		universe.Put(mock.GenerateMockedOutlet(outletID, settings))
+	}
+	return universe
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// Start serves s on a local port until the test ends and returns its base URL
+func (s *Server) Start(t testing.TB) string {
+	t.Helper()
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// SecretKey returns the key requests have to present
+func (s *Server) SecretKey() string {
+	return s.secretKey
+}
+
+// Settings returns the defaults for generated outlets
+func (s *Server) Settings() mock.MockSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.settings
+}
+
+// SetSettings replaces the defaults for generated outlets, request bodies can
+// still override single fields. Settings without Products keep the fixture products.
+func (s *Server) SetSettings(settings mock.MockSettings) {
+	if len(settings.Products) == 0 {
+		settings.Products = s.fixtures.Products
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Cache keys leave the products out, responses with other ones have to go
+	if !slices.Equal(settings.Products, s.settings.Products) {
+		s.FlushCache()
+	}
+	s.settings = settings
+}
+
+// Outlets returns the stateful universe, or nil when outlets are generated per request
+func (s *Server) Outlets() *store.Store {
+	return s.store
+}
+
+// Snapshot saves the stateful universe to path, or to Options.SnapshotPath
+// when path is empty, and returns the number of outlets saved
+func (s *Server) Snapshot(path string) (int, error) {
+	if s.store == nil {
+		return 0, ErrNotStateful
+	}
+	if path == "" {
+		path = s.snapshotPath
+	}
+	if path == "" {
+		return 0, errors.New("No snapshot path configured")
+	}
+	if err := s.store.Save(path); err != nil {
+		return 0, err
+	}
+	return s.store.Len(), nil
+}
+
+// CacheStats reports the response cache usage, all zero when caching is off
+func (s *Server) CacheStats() cache.Stats {
+	if s.cache == nil {
+		return cache.Stats{}
+	}
+	return s.cache.Stats()
+}
+
+// FlushCache drops every cached response
+func (s *Server) FlushCache() {
+	if s.cache != nil {
+		s.cache.Flush()
+	}
+}
+
+// InjectFault adds a fault injection rule and returns it with its ID
+func (s *Server) InjectFault(rule fault.Rule) (fault.Rule, error) {
+	return s.faults.Add(rule)
+}
+
+// RemoveFault deletes a fault rule, reporting whether it existed
+func (s *Server) RemoveFault(id string) bool {
+	return s.faults.Remove(id)
+}
+
+func (s *Server) ClearFaults() {
+	s.faults.Clear()
+}
+
+func (s *Server) Faults() []fault.Rule {
+	return s.faults.Rules()
+}
+
+func (s *Server) LoadFaults(path string) error {
+	return s.faults.LoadFile(path)
+}
+
+// ForceConflict makes writes to the records a rule matches fail with a
+// conflict and returns the rule with its ID
+func (s *Server) ForceConflict(rule conflict.Rule) (conflict.Rule, error) {
+	return s.conflicts.Add(rule)
+}
+
+// RemoveConflict deletes a conflict rule, reporting whether it existed
+func (s *Server) RemoveConflict(id string) bool {
+	return s.conflicts.Remove(id)
+}
+
+func (s *Server) ClearConflicts() {
+	s.conflicts.Clear()
+}
+
+func (s *Server) Conflicts() []conflict.Rule {
+	return s.conflicts.Rules()
+}
+
+// ResetIdempotencyKeys forgets the stored results of writes, retries are processed again
+func (s *Server) ResetIdempotencyKeys() {
+	s.idempotency.Clear()
+}
+
+// SetLatency applies latency profiles and route bindings
+func (s *Server) SetLatency(config latency.Config) error {
+	return s.latency.Apply(config)
+}
+
+// ResetLatency restores the built-in latency profiles
+func (s *Server) ResetLatency() {
+	s.latency.Reset()
+}
+
+func (s *Server) LoadLatency(path string) error {
+	return s.latency.LoadFile(path)
+}
+
+// PutScenario adds or replaces a scenario timeline
+func (s *Server) PutScenario(sc scenario.Scenario) error {
+	return s.scenarios.Put(sc)
+}
+
+// ResetScenarios restarts scenarios from their first step, empty name and
+// client match all of them
+func (s *Server) ResetScenarios(name, clientID string) {
+	s.scenarios.Reset(name, clientID)
+}
+
+func (s *Server) ClearScenarios() {
+	s.scenarios.Clear()
+}
+
+func (s *Server) LoadScenarios(path string) error {
+	return s.scenarios.LoadFile(path)
+}
+
+// PutRateLimit adds or replaces a rate limit
+func (s *Server) PutRateLimit(limit ratelimit.Limit) error {
+	return s.limiter.Put(limit)
+}
+
+func (s *Server) ClearRateLimits() {
+	s.limiter.Clear()
+}
+
+func (s *Server) LoadRateLimits(path string) error {
+	return s.limiter.LoadFile(path)
+}
+
+// AddStub adds a stub mapping and returns it with its ID and priority
+func (s *Server) AddStub(mapping stub.Mapping) (stub.Mapping, error) {
+	return s.stubs.Add(mapping)
+}
+
+// RemoveStub deletes a stub mapping, reporting whether it existed
+func (s *Server) RemoveStub(id string) bool {
+	return s.stubs.Remove(id)
+}
+
+func (s *Server) ClearStubs() {
+	s.stubs.Clear()
+}
+
+func (s *Server) Stubs() []stub.Mapping {
+	return s.stubs.Mappings()
+}
+
+func (s *Server) LoadStubs(dir string) error {
+	return s.stubs.LoadDir(dir)
+}
+
+// Requests returns the journaled requests matching filter, oldest first
+func (s *Server) Requests(filter journal.Filter) []journal.Entry {
+	return s.journal.Find(filter)
+}
+
+// CountRequests counts the journaled requests matching filter
+func (s *Server) CountRequests(filter journal.Filter) int {
+	return s.journal.Count(filter)
+}
+
+func (s *Server) ResetRequests() {
+	s.journal.Reset()
+}
+
+// AppendJournalTo additionally appends every journaled request to a JSON Lines file
+func (s *Server) AppendJournalTo(path string) error {
+	return s.journal.AppendTo(path)
+}
+
+// EndStalls closes every connection held by a stall fault. http.Server.Shutdown
+// waits for them otherwise, register it with RegisterOnShutdown. Stalls
+// started afterwards end right away.
+func (s *Server) EndStalls() {
+	s.endStalls()
+}
+
+// Close ends stalls, stops the background changes and releases the journal file, if any
+func (s *Server) Close() error {
+	s.endStalls()
+	if s.stopBackground != nil {
+		s.stopBackground()
+	}
+	return s.journal.Close()
+}
@@ -0,0 +1,121 @@
+package mockserver
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"srv-eazle-advise-mock/pkg/fault"
+	"srv-eazle-advise-mock/pkg/journal"
+	"srv-eazle-advise-mock/pkg/stub"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// request sends a request with the default secret key unless headers set
+// X-API-Key, and returns the response with its body read
+func request(t *testing.T, method, url, body string, headers map[string]string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-API-Key", DefaultSecretKey)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, data
+}
+
+// decode unmarshals a protobuf response body
+func decode(t *testing.T, data []byte, msg proto.Message) {
+	t.Helper()
+	if err := proto.Unmarshal(data, msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	baseURL := New(Options{}).Start(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+		want    int
+	}{
+		{name: "X-API-Key", path: "/outlets/outlet-001/news", want: http.StatusOK},
+		{name: "bearer token", path: "/outlets/outlet-001/news", headers: map[string]string{"X-API-Key": "", "Authorization": "Bearer " + DefaultSecretKey}, want: http.StatusOK},
+		{name: "wrong key", path: "/outlets/outlet-001/news", headers: map[string]string{"X-API-Key": "nope"}, want: http.StatusUnauthorized},
+		{name: "no key", path: "/outlets/outlet-001/news", headers: map[string]string{"X-API-Key": ""}, want: http.StatusUnauthorized},
+		{name: "admin API without key", path: "/__admin/requests", headers: map[string]string{"X-API-Key": ""}, want: http.StatusUnauthorized},
+		{name: "health without key", path: "/health", headers: map[string]string{"X-API-Key": ""}, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := request(t, "GET", baseURL+tt.path, "", tt.headers)
+			if resp.StatusCode != tt.want {
+				t.Errorf("GET %s: status %d, want %d", tt.path, resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestStubs(t *testing.T) {
+	server := New(Options{})
+	baseURL := server.Start(t)
+	mapping, err := server.AddStub(stub.Mapping{
+		Request:  stub.RequestPattern{Method: "GET", Path: "/promotions", Query: map[string]stub.Values{"tag": {"a", "b"}}},
+		Response: stub.Response{Status: http.StatusCreated, Body: "stubbed"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		headers    map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "match", query: "?tag=a&tag=b", wantStatus: http.StatusCreated, wantBody: "stubbed"},
+		{name: "missing query value", query: "?tag=a", wantStatus: http.StatusNotFound},
+		{name: "no key", query: "?tag=a&tag=b", headers: map[string]string{"X-API-Key": ""}, wantStatus: http.StatusUnauthorized},
+		{name: "fault", query: "?tag=a&tag=b", headers: map[string]string{"X-Fault-Status": "503"}, wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := request(t, "GET", baseURL+"/promotions"+tt.query, "", tt.headers)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantBody != "" && string(body) != tt.wantBody {
+				t.Errorf("body %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+
+	if _, err := server.InjectFault(fault.Rule{Route: "/promotions", Mode: fault.ModeCorrupt, Bytes: 3}); err != nil {
+		t.Fatal(err)
+	}
+	resp, body := request(t, "GET", baseURL+"/promotions?tag=a&tag=b", "", nil)
+	if resp.StatusCode != http.StatusCreated || string(body) == "stubbed" || len(body) != len("stubbed") {
+		t.Errorf("network fault on a stub: status %d, body %q; want 201 and a corrupted body", resp.StatusCode, body)
+	}
+
+	routes := server.Requests(journal.Filter{Path: "/promotions"})
+	if len(routes) == 0 || routes[0].Route != "stub:"+mapping.ID {
+		t.Errorf("journal route of a stubbed request: %+v, want stub:%s", routes, mapping.ID)
+	}
+}
@@ -0,0 +1,179 @@
+package mockserver
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"srv-eazle-advise-mock/pkg/cache"
+	"srv-eazle-advise-mock/pkg/fault"
+	"srv-eazle-advise-mock/pkg/fieldmask"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// outletWriter sends the outlets of a response one at a time, finish completes it
+type outletWriter interface {
+	write(outlet *pb.OutletDetails) error
+	finish()
+}
+
+// newOutletWriter streams the outlets when the request asks for a streaming
+// format and buffers the whole response otherwise. Outlets are pruned to mask.
+func newOutletWriter(w http.ResponseWriter, r *http.Request, networkFault fault.Rule, mask *fieldmask.Mask) outletWriter {
+	format, ok := selectStreamFormat(r)
+	if !ok {
+		return &bufferedWriter{w: w, r: r, networkFault: networkFault, mask: mask, outlets: &pb.OutletDetailsResponse{Details: []*pb.OutletDetails{}}}
+	}
+	stream := &streamWriter{w: w, r: r, format: format, networkFault: networkFault, mask: mask, out: w}
+	if networkFault.Mode != "" {
+		// Network faults break the complete body, so it is collected first
+		stream.buffer = &bytes.Buffer{}
+		stream.out = stream.buffer
+	}
+	return stream
+}
+
+// bufferedWriter collects the outlets and sends them as one OutletDetailsResponse
+type bufferedWriter struct {
+	w            http.ResponseWriter
+	r            *http.Request
+	networkFault fault.Rule
+	mask         *fieldmask.Mask
+	outlets      *pb.OutletDetailsResponse
+	encoded      func(entry cache.Entry) // optional, receives the encoded response
+}
+
+func (b *bufferedWriter) write(outlet *pb.OutletDetails) error {
+	b.mask.Prune(outlet)
+	b.outlets.Details = append(b.outlets.Details, outlet)
+	return nil
+}
+
+func (b *bufferedWriter) finish() {
+	data, err := encodeResponse(b.r, b.outlets)
+	if err != nil {
+		http.Error(b.w, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+	entry := newEntry(data, b.outlets.Details)
+	if b.encoded != nil {
+		b.encoded(entry)
+	}
+	writeEncoded(b.w, b.r, encodedContentType(b.r), entry, b.networkFault)
+}
+
+// streamFormat is a response format that can be written outlet by outlet
+type streamFormat struct {
+	contentType string
+	begin, end  string // written around the outlets
+	separator   string // written between outlets
+	encode      func(w io.Writer, outlet *pb.OutletDetails) error
+}
+
+var (
+	// ndjsonFormat writes one protojson OutletDetails per line
+	ndjsonFormat = streamFormat{
+		contentType: "application/x-ndjson",
+		encode: func(w io.Writer, outlet *pb.OutletDetails) error {
+			data, err := protojson.Marshal(outlet)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(append(data, '\n'))
+			return err
+		},
+	}
+
+	// delimitedFormat writes every OutletDetails prefixed with its varint size,
+	// as read by protodelim.UnmarshalFrom or Java's parseDelimitedFrom
+	delimitedFormat = streamFormat{
+		contentType: "application/x-protobuf-delimited",
+		encode: func(w io.Writer, outlet *pb.OutletDetails) error {
+			_, err := protodelim.MarshalTo(w, outlet)
+			return err
+		},
+	}
+
+	// jsonArrayFormat writes a regular OutletDetailsResponse in chunks
+	jsonArrayFormat = streamFormat{
+		contentType: "application/json",
+		begin:       `{"details":[`,
+		end:         "]}",
+		separator:   ",",
+		encode: func(w io.Writer, outlet *pb.OutletDetails) error {
+			data, err := protojson.Marshal(outlet)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		},
+	}
+)
+
+// selectStreamFormat picks a streaming format from the Accept header, or the
+// chunked JSON array when X-Stream is true and the client accepts JSON
+func selectStreamFormat(r *http.Request) (streamFormat, bool) {
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, ndjsonFormat.contentType):
+		return ndjsonFormat, true
+	case strings.Contains(accept, delimitedFormat.contentType):
+		return delimitedFormat, true
+	case r.Header.Get("X-Stream") == "true" && accept != "application/protobuf":
+		return jsonArrayFormat, true
+	}
+	return streamFormat{}, false
+}
+
+// streamWriter writes and flushes every outlet as soon as it is generated
+type streamWriter struct {
+	w            http.ResponseWriter
+	r            *http.Request
+	format       streamFormat
+	networkFault fault.Rule
+	mask         *fieldmask.Mask
+
+	out     io.Writer
+	buffer  *bytes.Buffer // only set with a network fault
+	written int
+}
+
+func (s *streamWriter) write(outlet *pb.OutletDetails) error {
+	s.mask.Prune(outlet)
+	if s.written == 0 {
+		s.start()
+	} else if _, err := io.WriteString(s.out, s.format.separator); err != nil {
+		return err
+	}
+	if err := s.format.encode(s.out, outlet); err != nil {
+		return err
+	}
+	s.written++
+	if s.buffer == nil {
+		http.NewResponseController(s.w).Flush()
+	}
+	return nil
+}
+
+func (s *streamWriter) start() {
+	s.w.Header().Set("Content-Type", s.format.contentType)
+	if s.buffer == nil {
+		s.w.WriteHeader(http.StatusOK)
+	}
+	io.WriteString(s.out, s.format.begin)
+}
+
+func (s *streamWriter) finish() {
+	if s.written == 0 {
+		s.start()
+	}
+	io.WriteString(s.out, s.format.end)
+	if s.buffer != nil {
+		fault.WriteBody(s.w, s.r, s.networkFault, http.StatusOK, s.buffer.Bytes())
+	}
+}
@@ -0,0 +1,144 @@
+package mockserver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"srv-eazle-advise-mock/pkg/fault"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/store"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// handleSyncOutlets returns the changes to the stateful universe since the
+// ?since= token, or the whole universe without one, plus the token to continue from
+func (s *Server) handleSyncOutlets(w http.ResponseWriter, r *http.Request, networkFault fault.Rule) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.store == nil {
+		http.Error(w, "Sync is only available in stateful mode", http.StatusConflict)
+		return
+	}
+
+	response := &pb.SyncOutletsResponse{}
+	since := r.URL.Query().Get("since")
+	if since == "" {
+		// The revision is read first, changes made while listing are sent again next time
+		response.NextToken = s.syncToken(s.store.Revision())
+		response.Outlets = s.store.List()
+		response.Full = true
+	} else {
+		revision, err := s.parseSyncToken(since)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		changes, current, ok := s.store.Changes(revision)
+		if !ok {
+			http.Error(w, "Sync token expired, sync again without since", http.StatusGone)
+			return
+		}
+		s.collectChanges(response, changes)
+		response.NextToken = s.syncToken(current)
+	}
+
+	data, err := encodeResponse(r, response)
+	if err != nil {
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+	writeEncoded(w, r, encodedContentType(r), newEntry(data, response.Outlets), networkFault)
+}
+
+// collectChanges adds the current state of every changed outlet and record to
+// response. Complete outlets already carry their records, deleted ones become tombstones.
+func (s *Server) collectChanges(response *pb.SyncOutletsResponse, changes []store.Change) {
+	type recordKey struct {
+		recordType pb.SyncRecordType
+		outletID   string
+		recordID   string
+	}
+	// Only the latest change of a record counts
+	latest := map[recordKey]store.Change{}
+	outlets := map[string]bool{}
+	for _, change := range changes {
+		latest[recordKey{change.Type, change.OutletID, change.RecordID}] = change
+		if change.Type == pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET {
+			outlets[change.OutletID] = true
+		}
+	}
+
+	for _, change := range changes {
+		key := recordKey{change.Type, change.OutletID, change.RecordID}
+		if latest[key].Revision != change.Revision {
+			continue
+		}
+		if change.Type != pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET && outlets[change.OutletID] {
+			continue
+		}
+		if !change.Deleted && s.addChange(response, change) {
+			continue
+		}
+		response.Deleted = append(response.Deleted, &pb.Tombstone{
+			Type:      change.Type,
+			OutletId:  change.OutletID,
+			RecordId:  change.RecordID,
+			DeletedAt: timestamppb.New(change.Time),
+		})
+	}
+}
+
+// addChange adds the current state of the changed outlet or record, reporting
+// false when it no longer exists
+func (s *Server) addChange(response *pb.SyncOutletsResponse, change store.Change) bool {
+	if change.Type == pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET {
+		outlet, ok := s.store.Get(change.OutletID)
+		if ok {
+			response.Outlets = append(response.Outlets, outlet)
+		}
+		return ok
+	}
+
+	record, ok := s.store.Record(change.OutletID, change.Type, change.RecordID)
+	if !ok {
+		return false
+	}
+	changedAt := timestamppb.New(change.Time)
+	switch record := record.(type) {
+	case *pb.Note:
+		response.Notes = append(response.Notes, &pb.NoteChange{OutletId: change.OutletID, Note: record, ChangedAt: changedAt})
+	case *pb.Visit:
+		response.Visits = append(response.Visits, &pb.VisitChange{OutletId: change.OutletID, Visit: record, ChangedAt: changedAt})
+	case *pb.Order:
+		response.Orders = append(response.Orders, &pb.OrderChange{OutletId: change.OutletID, Order: record, ChangedAt: changedAt})
+	case *pb.ChecklistItem:
+		response.Checklist = append(response.Checklist, &pb.ChecklistItemChange{OutletId: change.OutletID, Item: record, ChangedAt: changedAt})
+	}
+	return true
+}
+
+// syncToken encodes a revision of the store, tokens of another store are rejected
+func (s *Server) syncToken(revision int64) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d.%d", s.store.Epoch(), revision))
+}
+
+// parseSyncToken decodes a token from syncToken. Tokens of an earlier store
+// are mapped past the current revision so Changes reports them as expired.
+func (s *Server) parseSyncToken(token string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid sync token")
+	}
+	var epoch, revision int64
+	if _, err := fmt.Sscanf(string(data), "%d.%d", &epoch, &revision); err != nil || revision < 0 {
+		return 0, fmt.Errorf("Invalid sync token")
+	}
+	if epoch != s.store.Epoch() {
+		return s.store.Revision() + 1, nil
+	}
+	return revision, nil
+}
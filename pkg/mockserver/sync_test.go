@@ -0,0 +1,55 @@
+package mockserver
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+)
+
+func TestSyncOutlets(t *testing.T) {
+	server := New(Options{Stateful: true, Outlets: 3})
+	baseURL := server.Start(t)
+	headers := map[string]string{"Accept": "application/protobuf"}
+
+	resp, body := request(t, "GET", baseURL+"/sync/outlets", "", headers)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("full sync: status %d: %s", resp.StatusCode, body)
+	}
+	full := &pb.SyncOutletsResponse{}
+	decode(t, body, full)
+	if !full.Full || len(full.Outlets) != 3 {
+		t.Fatalf("full sync: full %v with %d outlets, want true with 3", full.Full, len(full.Outlets))
+	}
+
+	server.Outlets().Delete("outlet-002")
+	resp, body = request(t, "GET", baseURL+"/sync/outlets?since="+url.QueryEscape(full.NextToken), "", headers)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("delta sync: status %d: %s", resp.StatusCode, body)
+	}
+	delta := &pb.SyncOutletsResponse{}
+	decode(t, body, delta)
+	if delta.Full || len(delta.Outlets) != 0 || len(delta.Deleted) != 1 {
+		t.Errorf("delta sync: full %v, %d outlets, %d deletions; want false, 0, 1", delta.Full, len(delta.Outlets), len(delta.Deleted))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{name: "current token", token: delta.NextToken, want: http.StatusOK},
+		{name: "token of another store", token: New(Options{Stateful: true, Outlets: 1}).syncToken(0), want: http.StatusGone},
+		{name: "future revision", token: server.syncToken(server.Outlets().Revision() + 1), want: http.StatusGone},
+		{name: "garbage", token: "%%%", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := request(t, "GET", baseURL+"/sync/outlets?since="+url.QueryEscape(tt.token), "", headers)
+			if resp.StatusCode != tt.want {
+				t.Errorf("status %d, want %d: %s", resp.StatusCode, tt.want, body)
+			}
+		})
+	}
+}
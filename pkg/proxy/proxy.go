@@ -0,0 +1,219 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/stub"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Proxy forwards requests to an upstream server and optionally records every
+// exchange as a stub mapping, so it can be replayed offline with -mappings
+type Proxy struct {
+	reverseProxy *httputil.ReverseProxy
+	recordDir    string
+
+	mu   sync.Mutex
+	next int
+}
+
+type requestBodyKey struct{}
+
+// New creates a proxy to upstream. With recordDir set every response is
+// written there as a mapping file.
+func New(upstream, recordDir string) (*Proxy, error) {
+	target, err := url.Parse(upstream)
+	if err != nil {
+		return nil, err
+	}
+	if target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("upstream %q needs a scheme and host", upstream)
+	}
+	if recordDir != "" {
+		if err := os.MkdirAll(recordDir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &Proxy{recordDir: recordDir}
+	p.reverseProxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.Out.Host = target.Host
+			if recordDir != "" {
+				// Recordings should hold plain bodies, not whatever encoding the upstream prefers
+				r.Out.Header.Del("Accept-Encoding")
+			}
+		},
+	}
+	if recordDir != "" {
+		p.reverseProxy.ModifyResponse = p.record
+	}
+	return p, nil
+}
+
+// Handler forwards every request to the upstream, except the admin API under
+// /__admin/ which is served by local. Forwarded requests are answered by the
+// upstream alone, the mock's auth, rate limits, scenarios, latency and faults
+// don't apply to them.
+func (p *Proxy) Handler(local http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/__admin/") {
+			local.ServeHTTP(w, r)
+			return
+		}
+
+		if p.recordDir != "" && r.Body != nil {
+			// The outgoing request consumes the body, keep a copy for the recording
+			body, _ := io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r = r.WithContext(context.WithValue(r.Context(), requestBodyKey{}, body))
+		}
+		p.reverseProxy.ServeHTTP(w, r)
+	})
+}
+
+// record writes the exchange as a mapping file, leaving the response untouched
+func (p *Proxy) record(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	requestBody, _ := resp.Request.Context().Value(requestBodyKey{}).([]byte)
+	mapping := stub.Mapping{
+		Request:  requestPattern(resp.Request, requestBody),
+		Response: p.response(resp, body),
+	}
+
+	data, err := json.MarshalIndent(mapping, "", "  ")
+	if err != nil {
+		log.Printf("Failed to record %s %s: %v", resp.Request.Method, resp.Request.URL.Path, err)
+		return nil
+	}
+	path := p.nextPath(resp.Request)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		log.Printf("Failed to record %s %s: %v", resp.Request.Method, resp.Request.URL.Path, err)
+		return nil
+	}
+	fmt.Printf("Recorded %s %s to %s\n", resp.Request.Method, resp.Request.URL.Path, path)
+	return nil
+}
+
+// recordedHeaders change what the mock and most upstreams answer, they are
+// part of the recorded request pattern when present
+var recordedHeaders = []string{"Accept", "Accept-Language", "X-Outlet-Num", "X-Seed"}
+
+// requestPattern matches the recorded request on method, path, query, the
+// recordedHeaders and JSON body
+func requestPattern(r *http.Request, body []byte) stub.RequestPattern {
+	pattern := stub.RequestPattern{
+		Method: r.Method,
+		Path:   r.URL.Path,
+	}
+	for name, values := range r.URL.Query() {
+		if pattern.Query == nil {
+			pattern.Query = map[string]stub.Values{}
+		}
+		pattern.Query[name] = values
+	}
+	for _, name := range recordedHeaders {
+		if value := r.Header.Get(name); value != "" {
+			if pattern.Headers == nil {
+				pattern.Headers = map[string]string{}
+			}
+			pattern.Headers[name] = value
+		}
+	}
+	if len(bytes.TrimSpace(body)) > 0 && json.Valid(body) {
+		pattern.Body = []stub.BodyPattern{{JSONPath: "$", Equals: body}}
+	}
+	return pattern
+}
+
+// response keeps a body as the most specific kind of stub.Response. Outlet
+// payloads are kept both raw and decoded to editable protojson.
+func (p *Proxy) response(resp *http.Response, body []byte) stub.Response {
+	response := stub.Response{Status: resp.StatusCode, Headers: map[string]string{}}
+	for _, name := range []string{"Content-Type", "Cache-Control", "Retry-After"} {
+		if value := resp.Header.Get(name); value != "" {
+			response.Headers[name] = value
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if outlets, ok := decodeOutlets(mediaType, body); ok {
+		// The raw body is replayed until the decoded outlets are edited
+		response.Outlets = outlets
+		response.Base64Body = base64.StdEncoding.EncodeToString(body)
+		return response
+	}
+
+	switch {
+	case len(body) == 0:
+	case mediaType == "application/json" && json.Valid(body):
+		response.JSONBody = body
+	case utf8.Valid(body) && !strings.Contains(mediaType, "protobuf"):
+		response.Body = string(body)
+	default:
+		response.Base64Body = base64.StdEncoding.EncodeToString(body)
+	}
+	return response
+}
+
+// decodeOutlets turns an OutletDetailsResponse payload into protojson
+func decodeOutlets(mediaType string, body []byte) (json.RawMessage, bool) {
+	outlets := &pb.OutletDetailsResponse{}
+	var err error
+	switch mediaType {
+	case "application/protobuf", "application/x-protobuf":
+		err = proto.Unmarshal(body, outlets)
+	case "application/json":
+		err = protojson.Unmarshal(body, outlets)
+	default:
+		return nil, false
+	}
+	if err != nil || len(outlets.Details) == 0 {
+		return nil, false
+	}
+	data, err := protojson.Marshal(outlets)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// nextPath names recordings after the request, e.g. get-outlets-001.json
+func (p *Proxy) nextPath(r *http.Request) string {
+	name := strings.ToLower(r.Method) + "-" + strings.Trim(strings.ReplaceAll(r.URL.Path, "/", "-"), "-")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		p.next++
+		path := filepath.Join(p.recordDir, fmt.Sprintf("%s-%03d.json", name, p.next))
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket for requests of an API key to a route. Every request
+// takes a token, tokens refill at RefillPerSecond up to Capacity.
+type Limit struct {
+	Key             string  `json:"key,omitempty"`   // API key, empty matches every key
+	Route           string  `json:"route,omitempty"` // path prefix, empty matches every route
+	Capacity        int     `json:"capacity"`
+	RefillPerSecond float64 `json:"refillPerSecond"`
+}
+
+func (l Limit) Validate() error {
+	if l.Capacity < 1 {
+		return fmt.Errorf("capacity must be at least 1")
+	}
+	if l.RefillPerSecond <= 0 {
+		return fmt.Errorf("refillPerSecond must be positive")
+	}
+	return nil
+}
+
+func (l Limit) matches(key, path string) bool {
+	return (l.Key == "" || l.Key == key) && strings.HasPrefix(path, l.Route)
+}
+
+// moreSpecific reports whether l should win over other: an exact key beats
+// a wildcard, then the longer route wins
+func (l Limit) moreSpecific(other Limit) bool {
+	if (l.Key != "") != (other.Key != "") {
+		return l.Key != ""
+	}
+	return len(l.Route) > len(other.Route)
+}
+
+// State is the bucket of one API key under one limit
+type State struct {
+	Key          string  `json:"key"`
+	Route        string  `json:"route"`
+	Limit        int     `json:"limit"`
+	Tokens       float64 `json:"tokens"`
+	Remaining    int     `json:"remaining"`
+	ResetSeconds int     `json:"resetSeconds"` // until the bucket is full again
+	RetryAfter   int     `json:"retryAfterSeconds,omitempty"`
+}
+
+type bucket struct {
+	limit  Limit
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens earned since the last request
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(float64(b.limit.Capacity), b.tokens+now.Sub(b.last).Seconds()*b.limit.RefillPerSecond)
+	b.last = now
+}
+
+func (b *bucket) state() State {
+	state := State{
+		Key:          b.key,
+		Route:        b.limit.Route,
+		Limit:        b.limit.Capacity,
+		Tokens:       math.Round(b.tokens*100) / 100,
+		Remaining:    int(b.tokens),
+		ResetSeconds: int(math.Ceil((float64(b.limit.Capacity) - b.tokens) / b.limit.RefillPerSecond)),
+	}
+	if b.tokens < 1 {
+		state.RetryAfter = max(int(math.Ceil((1-b.tokens)/b.limit.RefillPerSecond)), 1)
+	}
+	return state
+}
+
+// Limiter holds the configured limits and a bucket per API key and limit
+type Limiter struct {
+	mu      sync.Mutex
+	limits  []Limit
+	buckets map[string]*bucket // key + "\x00" + limit key + "\x00" + route
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: map[string]*bucket{}}
+}
+
+// Put adds a limit or replaces the one for the same key and route, which
+// starts its buckets over
+func (l *Limiter) Put(limit Limit) error {
+	if err := limit.Validate(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.dropBuckets(limit.Key, limit.Route)
+	for i, existing := range l.limits {
+		if existing.Key == limit.Key && existing.Route == limit.Route {
+			l.limits[i] = limit
+			return nil
+		}
+	}
+	l.limits = append(l.limits, limit)
+	return nil
+}
+
+// Remove deletes the limit for key and route, reporting whether it existed
+func (l *Limiter) Remove(key, route string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, limit := range l.limits {
+		if limit.Key == key && limit.Route == route {
+			l.limits = append(l.limits[:i], l.limits[i+1:]...)
+			l.dropBuckets(key, route)
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Limiter) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = nil
+	l.buckets = map[string]*bucket{}
+}
+
+func (l *Limiter) dropBuckets(key, route string) {
+	for id, b := range l.buckets {
+		if b.limit.Key == key && b.limit.Route == route {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+func (l *Limiter) Limits() []Limit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Limit{}, l.limits...)
+}
+
+// Buckets returns the current state of every bucket that has seen a request
+func (l *Limiter) Buckets() []State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	states := []State{}
+	for _, b := range l.buckets {
+		b.refill(now)
+		states = append(states, b.state())
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Key != states[j].Key {
+			return states[i].Key < states[j].Key
+		}
+		return states[i].Route < states[j].Route
+	})
+	return states
+}
+
+// LoadFile adds the limits from a JSON file containing an array of limits
+func (l *Limiter) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var limits []Limit
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, limit := range limits {
+		if err := l.Put(limit); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Take spends a token of the most specific limit for key and path. It reports
+// whether the request is allowed and whether any limit applies at all.
+func (l *Limiter) Take(key, path string) (state State, allowed, limited bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var limit Limit
+	for _, candidate := range l.limits {
+		if candidate.matches(key, path) && (!limited || candidate.moreSpecific(limit)) {
+			limit, limited = candidate, true
+		}
+	}
+	if !limited {
+		return State{}, true, false
+	}
+
+	now := time.Now()
+	id := key + "\x00" + limit.Key + "\x00" + limit.Route
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{limit: limit, key: key, tokens: float64(limit.Capacity), last: now}
+		l.buckets[id] = b
+	}
+	b.refill(now)
+
+	if b.tokens < 1 {
+		return b.state(), false, true
+	}
+	b.tokens--
+	return b.state(), true, true
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketRefill(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name    string
+		tokens  float64
+		elapsed time.Duration
+		want    float64
+	}{
+		{name: "nothing elapsed", tokens: 1, elapsed: 0, want: 1},
+		{name: "partial token", tokens: 0, elapsed: 250 * time.Millisecond, want: 0.5},
+		{name: "whole tokens", tokens: 1, elapsed: 2 * time.Second, want: 5},
+		{name: "capped at capacity", tokens: 3, elapsed: time.Minute, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &bucket{limit: Limit{Capacity: 10, RefillPerSecond: 2}, tokens: tt.tokens, last: start}
+			b.refill(start.Add(tt.elapsed))
+			if b.tokens != tt.want {
+				t.Errorf("tokens = %g, want %g", b.tokens, tt.want)
+			}
+			if !b.last.Equal(start.Add(tt.elapsed)) {
+				t.Errorf("last = %v, want the refill time", b.last)
+			}
+		})
+	}
+}
+
+func TestBucketState(t *testing.T) {
+	tests := []struct {
+		tokens         float64
+		wantRemaining  int
+		wantReset      int
+		wantRetryAfter int
+	}{
+		{tokens: 4, wantRemaining: 4, wantReset: 0},
+		{tokens: 2.5, wantRemaining: 2, wantReset: 1},
+		{tokens: 0.5, wantRemaining: 0, wantReset: 2, wantRetryAfter: 1},
+		{tokens: 0, wantRemaining: 0, wantReset: 2, wantRetryAfter: 1},
+	}
+	for _, tt := range tests {
+		b := &bucket{limit: Limit{Capacity: 4, RefillPerSecond: 2}, tokens: tt.tokens}
+		state := b.state()
+		if state.Remaining != tt.wantRemaining || state.ResetSeconds != tt.wantReset || state.RetryAfter != tt.wantRetryAfter {
+			t.Errorf("tokens %g: remaining %d, reset %d, retry after %d; want %d, %d, %d", tt.tokens,
+				state.Remaining, state.ResetSeconds, state.RetryAfter, tt.wantRemaining, tt.wantReset, tt.wantRetryAfter)
+		}
+	}
+}
+
+func TestTake(t *testing.T) {
+	limiter := NewLimiter()
+	for _, limit := range []Limit{
+		{Route: "/outlets", Capacity: 2, RefillPerSecond: 0.001},
+		{Key: "vip", Route: "/outlets", Capacity: 5, RefillPerSecond: 0.001},
+	} {
+		if err := limiter.Put(limit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		key, path   string
+		wantAllowed bool
+		wantLimited bool
+	}{
+		{"app", "/outlets", true, true},
+		{"app", "/outlets/outlet-001/notes", true, true},
+		{"app", "/outlets", false, true},
+		{"other", "/outlets", true, true}, // every key has its own bucket
+		{"vip", "/outlets", true, true},   // the exact key wins over the wildcard
+		{"vip", "/outlets", true, true},
+		{"vip", "/outlets", true, true},
+		{"app", "/sync/outlets", true, false},
+	}
+	for i, tt := range tests {
+		_, allowed, limited := limiter.Take(tt.key, tt.path)
+		if allowed != tt.wantAllowed || limited != tt.wantLimited {
+			t.Errorf("request %d (%s %s): allowed %v, limited %v; want %v, %v", i+1, tt.key, tt.path, allowed, limited, tt.wantAllowed, tt.wantLimited)
+		}
+	}
+}
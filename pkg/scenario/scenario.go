@@ -0,0 +1,265 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"srv-eazle-advise-mock/pkg/fault"
+)
+
+// Step is one phase of a scenario. It lasts for Requests requests or
+// DurationSeconds, whichever runs out first; with neither set it lasts forever.
+type Step struct {
+	Requests        int         `json:"requests,omitempty"`
+	DurationSeconds int         `json:"durationSeconds,omitempty"`
+	Fault           *fault.Rule `json:"fault,omitempty"`
+	DelayMs         int         `json:"delayMs,omitempty"`
+	// DelayRampMsPerMinute grows the delay the longer the step lasts
+	DelayRampMsPerMinute int    `json:"delayRampMsPerMinute,omitempty"`
+	LatencyProfile       string `json:"latencyProfile,omitempty"`
+}
+
+// Delay is the extra delay of the step after it has been active for elapsed
+func (s Step) Delay(elapsed time.Duration) time.Duration {
+	delay := time.Duration(s.DelayMs) * time.Millisecond
+	delay += time.Duration(float64(s.DelayRampMsPerMinute) * elapsed.Minutes() * float64(time.Millisecond))
+	return delay
+}
+
+// Scenario is a scripted timeline of steps, replayed separately for every
+// client. Once the last step is over the route behaves normally again, unless
+// Loop starts the timeline over.
+type Scenario struct {
+	Name    string   `json:"name"`
+	Route   string   `json:"route,omitempty"`   // path prefix, empty matches every route
+	Clients []string `json:"clients,omitempty"` // client IDs it applies to, empty means all
+	Loop    bool     `json:"loop,omitempty"`
+	Steps   []Step   `json:"steps"`
+}
+
+func (s Scenario) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("scenario name is required")
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("scenario %q has no steps", s.Name)
+	}
+	for i, step := range s.Steps {
+		if step.Requests < 0 || step.DurationSeconds < 0 || step.DelayMs < 0 {
+			return fmt.Errorf("scenario %q step %d: requests, durationSeconds and delayMs must not be negative", s.Name, i+1)
+		}
+		if step.Fault != nil {
+			if err := step.Fault.Validate(); err != nil {
+				return fmt.Errorf("scenario %q step %d: %w", s.Name, i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s Scenario) matches(path, clientID string) bool {
+	if s.Route != "" && !strings.HasPrefix(path, s.Route) {
+		return false
+	}
+	if len(s.Clients) == 0 {
+		return true
+	}
+	for _, client := range s.Clients {
+		if client == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// Active is the step a request falls into
+type Active struct {
+	Scenario string
+	Index    int // 0-based position of the step
+	Step     Step
+	Elapsed  time.Duration // time since the step started
+}
+
+// progress is where a client is in a scenario
+type progress struct {
+	step      int
+	requests  int
+	stepStart time.Time
+}
+
+// Engine holds the loaded scenarios and the progress of every client through them
+type Engine struct {
+	mu        sync.Mutex
+	scenarios []Scenario
+	progress  map[string]*progress // scenario name + "\x00" + client ID
+}
+
+func NewEngine() *Engine {
+	return &Engine{
+		progress: map[string]*progress{},
+	}
+}
+
+// Put adds a scenario or replaces the one with the same name, restarting it for all clients
+func (e *Engine) Put(scenario Scenario) error {
+	if err := scenario.Validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.resetLocked(scenario.Name, "")
+	for i, existing := range e.scenarios {
+		if existing.Name == scenario.Name {
+			e.scenarios[i] = scenario
+			return nil
+		}
+	}
+	e.scenarios = append(e.scenarios, scenario)
+	return nil
+}
+
+// Remove deletes the scenario with the given name, reporting whether it existed
+func (e *Engine) Remove(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, scenario := range e.scenarios {
+		if scenario.Name == name {
+			e.scenarios = append(e.scenarios[:i], e.scenarios[i+1:]...)
+			e.resetLocked(name, "")
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) Clear() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.scenarios = nil
+	e.progress = map[string]*progress{}
+}
+
+func (e *Engine) Scenarios() []Scenario {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Scenario{}, e.scenarios...)
+}
+
+// Reset restarts scenarios from their first step. Empty name or client ID
+// mean all scenarios or all clients.
+func (e *Engine) Reset(name, clientID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resetLocked(name, clientID)
+}
+
+func (e *Engine) resetLocked(name, clientID string) {
+	for key := range e.progress {
+		scenarioName, client, _ := strings.Cut(key, "\x00")
+		if (name == "" || scenarioName == name) && (clientID == "" || client == clientID) {
+			delete(e.progress, key)
+		}
+	}
+}
+
+// LoadFile adds the scenarios from a JSON file containing an array of scenarios
+func (e *Engine) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var scenarios []Scenario
+	if err := json.Unmarshal(data, &scenarios); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, scenario := range scenarios {
+		if err := e.Put(scenario); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Advance counts a request against its client's scenario and returns the step
+// it falls into. The client is identified by X-Client-Id, X-Scenario selects a
+// scenario by name instead of by route.
+func (e *Engine) Advance(r *http.Request) (Active, bool, error) {
+	clientID := r.Header.Get("X-Client-Id")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	scenario, ok := e.find(r, clientID)
+	if !ok {
+		if name := r.Header.Get("X-Scenario"); name != "" {
+			return Active{}, false, fmt.Errorf("Unknown scenario %q", name)
+		}
+		return Active{}, false, nil
+	}
+
+	now := time.Now()
+	key := scenario.Name + "\x00" + clientID
+	p, ok := e.progress[key]
+	if !ok {
+		p = &progress{stepStart: now}
+		e.progress[key] = p
+	}
+
+	for p.step < len(scenario.Steps) && stepOver(scenario.Steps[p.step], p, now) {
+		step := scenario.Steps[p.step]
+		// Time-based steps end at a fixed point so the timeline doesn't drift with request timing
+		if step.DurationSeconds > 0 && now.Sub(p.stepStart) >= time.Duration(step.DurationSeconds)*time.Second {
+			p.stepStart = p.stepStart.Add(time.Duration(step.DurationSeconds) * time.Second)
+		} else {
+			p.stepStart = now
+		}
+		p.step++
+		p.requests = 0
+		if p.step == len(scenario.Steps) && scenario.Loop {
+			p.step = 0
+		}
+	}
+	if p.step == len(scenario.Steps) {
+		return Active{}, false, nil
+	}
+
+	p.requests++
+	return Active{
+		Scenario: scenario.Name,
+		Index:    p.step,
+		Step:     scenario.Steps[p.step],
+		Elapsed:  now.Sub(p.stepStart),
+	}, true, nil
+}
+
+func (e *Engine) find(r *http.Request, clientID string) (Scenario, bool) {
+	if name := r.Header.Get("X-Scenario"); name != "" {
+		for _, scenario := range e.scenarios {
+			if scenario.Name == name {
+				return scenario, true
+			}
+		}
+		return Scenario{}, false
+	}
+	for _, scenario := range e.scenarios {
+		if scenario.matches(r.URL.Path, clientID) {
+			return scenario, true
+		}
+	}
+	return Scenario{}, false
+}
+
+func stepOver(step Step, p *progress, now time.Time) bool {
+	if step.Requests > 0 && p.requests >= step.Requests {
+		return true
+	}
+	return step.DurationSeconds > 0 && now.Sub(p.stepStart) >= time.Duration(step.DurationSeconds)*time.Second
+}
@@ -0,0 +1,112 @@
+package scenario
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdvance(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario Scenario
+		want     []int // step index per request, -1 when no step applies
+	}{
+		{
+			name:     "steps by request count",
+			scenario: Scenario{Name: "flaky", Steps: []Step{{Requests: 2}, {Requests: 1}}},
+			want:     []int{0, 0, 1, -1, -1},
+		},
+		{
+			name:     "loop starts over",
+			scenario: Scenario{Name: "flaky", Loop: true, Steps: []Step{{Requests: 1}, {Requests: 2}}},
+			want:     []int{0, 1, 1, 0, 1},
+		},
+		{
+			name:     "last step without limit lasts forever",
+			scenario: Scenario{Name: "outage", Steps: []Step{{Requests: 1}, {}}},
+			want:     []int{0, 1, 1, 1},
+		},
+		{
+			name:     "other route",
+			scenario: Scenario{Name: "sync", Route: "/sync", Steps: []Step{{}}},
+			want:     []int{-1, -1},
+		},
+		{
+			name:     "other client",
+			scenario: Scenario{Name: "ios", Clients: []string{"ios-app"}, Steps: []Step{{}}},
+			want:     []int{-1, -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine()
+			if err := engine.Put(tt.scenario); err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.want {
+				r := httptest.NewRequest("GET", "/outlets", nil)
+				r.Header.Set("X-Client-Id", "android-app")
+				active, ok, err := engine.Advance(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := -1
+				if ok {
+					got = active.Index
+				}
+				if got != want {
+					t.Errorf("request %d: step %d, want %d", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAdvanceSeparatesClients(t *testing.T) {
+	engine := NewEngine()
+	if err := engine.Put(Scenario{Name: "flaky", Steps: []Step{{Requests: 1}, {}}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, client := range []string{"a", "a", "b"} {
+		r := httptest.NewRequest("GET", "/outlets", nil)
+		r.Header.Set("X-Client-Id", client)
+		engine.Advance(r)
+	}
+	r := httptest.NewRequest("GET", "/outlets", nil)
+	r.Header.Set("X-Client-Id", "b")
+	if active, _, _ := engine.Advance(r); active.Index != 1 {
+		t.Errorf("second request of b is in step %d, want 1", active.Index)
+	}
+
+	engine.Reset("flaky", "b")
+	if active, _, _ := engine.Advance(r); active.Index != 0 {
+		t.Errorf("first request of b after Reset is in step %d, want 0", active.Index)
+	}
+}
+
+func TestAdvanceUnknownScenario(t *testing.T) {
+	r := httptest.NewRequest("GET", "/outlets", nil)
+	r.Header.Set("X-Scenario", "nope")
+	if _, _, err := NewEngine().Advance(r); err == nil {
+		t.Error("Advance with an unknown X-Scenario succeeded")
+	}
+}
+
+func TestDelay(t *testing.T) {
+	tests := []struct {
+		step    Step
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		{Step{}, time.Minute, 0},
+		{Step{DelayMs: 200}, time.Hour, 200 * time.Millisecond},
+		{Step{DelayMs: 100, DelayRampMsPerMinute: 60}, 2 * time.Minute, 220 * time.Millisecond},
+		{Step{DelayRampMsPerMinute: 60}, 30 * time.Second, 30 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := tt.step.Delay(tt.elapsed); got != tt.want {
+			t.Errorf("%+v.Delay(%v) = %v, want %v", tt.step, tt.elapsed, got, tt.want)
+		}
+	}
+}
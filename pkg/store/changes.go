@@ -0,0 +1,197 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxChanges bounds the change log, older sync tokens expire
+const maxChanges = 100000
+
+// Change is an entry of the change log: an outlet or one of its records was
+// created, updated or deleted at Revision
+type Change struct {
+	Revision int64
+	Type     pb.SyncRecordType
+	OutletID string
+	RecordID string // empty for outlets
+	Deleted  bool
+	Time     time.Time
+}
+
+// record appends a change to the log, the caller holds the lock
+func (s *Store) record(change Change) {
+	s.revision++
+	change.Revision = s.revision
+	change.Time = time.Now()
+	s.changes = append(s.changes, change)
+	s.bumpVersions(change)
+	if len(s.changes) > maxChanges {
+		s.changes = append([]Change(nil), s.changes[len(s.changes)-maxChanges/2:]...)
+	}
+}
+
+// Revision is the revision of the latest change
+func (s *Store) Revision() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.revision
+}
+
+// Epoch identifies the contents of this store, revisions of another store
+// (e.g. before a restart or Load) mean nothing to it
+func (s *Store) Epoch() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.epoch
+}
+
+// Changes returns the changes after revision since and the current revision.
+// ok is false when the log no longer reaches back that far or since is in the future.
+func (s *Store) Changes(since int64) (changes []Change, revision int64, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if since > s.revision {
+		return nil, s.revision, false
+	}
+	if len(s.changes) > 0 && since < s.changes[0].Revision-1 {
+		return nil, s.revision, false
+	}
+	for i := len(s.changes); i > 0; i-- {
+		if s.changes[i-1].Revision <= since {
+			return append([]Change(nil), s.changes[i:]...), s.revision, true
+		}
+	}
+	return append([]Change(nil), s.changes...), s.revision, true
+}
+
+// PutRecord inserts or replaces a note, visit, order or checklist item of an
+// outlet, matched on its ID, and bumps the outlet's UpdatedAt
+func (s *Store) PutRecord(outletID string, record proto.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outlet, ok := s.outlets[outletID]
+	if !ok {
+		return fmt.Errorf("Outlet %s not found", outletID)
+	}
+	record = proto.Clone(record)
+
+	var recordType pb.SyncRecordType
+	var recordID string
+	switch record := record.(type) {
+	case *pb.Note:
+		recordType, recordID = pb.SyncRecordType_SYNC_RECORD_TYPE_NOTE, record.NoteId
+		putRecord(&outlet.Notes, record, (*pb.Note).GetNoteId)
+	case *pb.Visit:
+		recordType, recordID = pb.SyncRecordType_SYNC_RECORD_TYPE_VISIT, record.VisitId
+		putRecord(&outlet.VisitHistory, record, (*pb.Visit).GetVisitId)
+	case *pb.Order:
+		recordType, recordID = pb.SyncRecordType_SYNC_RECORD_TYPE_ORDER, record.OrderId
+		putRecord(&outlet.OrderHistory, record, (*pb.Order).GetOrderId)
+	case *pb.ChecklistItem:
+		recordType, recordID = pb.SyncRecordType_SYNC_RECORD_TYPE_CHECKLIST_ITEM, record.ItemId
+		putRecord(&outlet.Checklist, record, (*pb.ChecklistItem).GetItemId)
+	default:
+		return fmt.Errorf("Unsupported record type %T", record)
+	}
+
+	outlet.UpdatedAt = timestamppb.Now()
+	s.record(Change{Type: recordType, OutletID: outletID, RecordID: recordID})
+	return nil
+}
+
+// DeleteRecord removes a record of an outlet, reporting whether it existed
+func (s *Store) DeleteRecord(outletID string, recordType pb.SyncRecordType, recordID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outlet, ok := s.outlets[outletID]
+	if !ok {
+		return false
+	}
+
+	var deleted bool
+	switch recordType {
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_NOTE:
+		deleted = deleteRecord(&outlet.Notes, recordID, (*pb.Note).GetNoteId)
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_VISIT:
+		deleted = deleteRecord(&outlet.VisitHistory, recordID, (*pb.Visit).GetVisitId)
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_ORDER:
+		deleted = deleteRecord(&outlet.OrderHistory, recordID, (*pb.Order).GetOrderId)
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_CHECKLIST_ITEM:
+		deleted = deleteRecord(&outlet.Checklist, recordID, (*pb.ChecklistItem).GetItemId)
+	}
+	if !deleted {
+		return false
+	}
+
+	outlet.UpdatedAt = timestamppb.Now()
+	s.record(Change{Type: recordType, OutletID: outletID, RecordID: recordID, Deleted: true})
+	return true
+}
+
+// Record returns a copy of a record of an outlet
+func (s *Store) Record(outletID string, recordType pb.SyncRecordType, recordID string) (proto.Message, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	outlet, ok := s.outlets[outletID]
+	if !ok {
+		return nil, false
+	}
+	record, ok := findOutletRecord(outlet, recordType, recordID)
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(record), true
+}
+
+func findOutletRecord(outlet *pb.OutletDetails, recordType pb.SyncRecordType, recordID string) (proto.Message, bool) {
+	switch recordType {
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_NOTE:
+		return findRecord(outlet.Notes, recordID, (*pb.Note).GetNoteId)
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_VISIT:
+		return findRecord(outlet.VisitHistory, recordID, (*pb.Visit).GetVisitId)
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_ORDER:
+		return findRecord(outlet.OrderHistory, recordID, (*pb.Order).GetOrderId)
+	case pb.SyncRecordType_SYNC_RECORD_TYPE_CHECKLIST_ITEM:
+		return findRecord(outlet.Checklist, recordID, (*pb.ChecklistItem).GetItemId)
+	}
+	return nil, false
+}
+
+func putRecord[T proto.Message](records *[]T, record T, id func(T) string) {
+	for i, existing := range *records {
+		if id(existing) == id(record) {
+			(*records)[i] = record
+			return
+		}
+	}
+	*records = append(*records, record)
+}
+
+func deleteRecord[T proto.Message](records *[]T, recordID string, id func(T) string) bool {
+	for i, existing := range *records {
+		if id(existing) == recordID {
+			*records = append((*records)[:i], (*records)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func findRecord[T proto.Message](records []T, recordID string, id func(T) string) (proto.Message, bool) {
+	for _, record := range records {
+		if id(record) == recordID {
+			return record, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+)
+
+func TestChanges(t *testing.T) {
+	s := New()
+	for _, id := range []string{"outlet-001", "outlet-002", "outlet-003"} {
+		s.Put(&pb.OutletDetails{OutletId: id})
+	}
+	s.Delete("outlet-002")
+
+	tests := []struct {
+		name    string
+		since   int64
+		want    []string // outlet IDs of the changes, - marks deletions
+		expired bool
+	}{
+		{name: "from the start", since: 0, want: []string{"outlet-001", "outlet-002", "outlet-003", "-outlet-002"}},
+		{name: "partway", since: 2, want: []string{"outlet-003", "-outlet-002"}},
+		{name: "up to date", since: 4, want: nil},
+		{name: "future revision", since: 5, expired: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, revision, ok := s.Changes(tt.since)
+			if ok == tt.expired {
+				t.Fatalf("Changes(%d) ok = %v, want %v", tt.since, ok, !tt.expired)
+			}
+			if revision != 4 {
+				t.Errorf("revision = %d, want 4", revision)
+			}
+			var got []string
+			for _, change := range changes {
+				id := change.OutletID
+				if change.Deleted {
+					id = "-" + id
+				}
+				got = append(got, id)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Changes(%d) = %v, want %v", tt.since, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Changes(%d) = %v, want %v", tt.since, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestChangesExpire(t *testing.T) {
+	s := New()
+	for _, id := range []string{"outlet-001", "outlet-002", "outlet-003"} {
+		s.Put(&pb.OutletDetails{OutletId: id})
+	}
+	// Trim the log the way record does once it outgrows maxChanges
+	s.changes = s.changes[2:]
+
+	if _, _, ok := s.Changes(0); ok {
+		t.Error("Changes before the trimmed log succeeded")
+	}
+	if changes, _, ok := s.Changes(2); !ok || len(changes) != 1 {
+		t.Errorf("Changes(2) = %d changes, ok %v; want 1, true", len(changes), ok)
+	}
+}
+
+func TestLoadStartsOver(t *testing.T) {
+	s := New()
+	s.Put(&pb.OutletDetails{OutletId: "outlet-001"})
+	s.Put(&pb.OutletDetails{OutletId: "outlet-002"})
+	path := filepath.Join(t.TempDir(), "snapshot.pb")
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	epoch := s.Epoch()
+
+	if err := s.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if s.Epoch() == epoch {
+		t.Error("Load kept the epoch")
+	}
+	if revision := s.Revision(); revision != 0 {
+		t.Errorf("Revision() = %d after Load, want 0", revision)
+	}
+	if changes, _, ok := s.Changes(0); !ok || len(changes) != 0 {
+		t.Errorf("Changes(0) = %d changes, ok %v after Load; want 0, true", len(changes), ok)
+	}
+	if n := s.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+}
@@ -0,0 +1,187 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Store holds the materialized outlet universe used in stateful mode.
+// Outlets are kept in insertion order so listings and snapshots are stable.
+// Every mutation is recorded in a change log for delta sync.
+type Store struct {
+	mu      sync.RWMutex
+	outlets map[string]*pb.OutletDetails
+	order   []string
+
+	epoch    int64
+	revision int64
+	changes  []Change
+	versions map[string]*outletVersions
+}
+
+func New() *Store {
+	return &Store{
+		outlets: map[string]*pb.OutletDetails{},
+		epoch:   time.Now().UnixNano(),
+	}
+}
+
+// Get returns a copy of the outlet so callers can't mutate stored state by accident
+func (s *Store) Get(outletID string) (*pb.OutletDetails, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	outlet, ok := s.outlets[outletID]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(outlet).(*pb.OutletDetails), true
+}
+
+// Put inserts or replaces an outlet
+func (s *Store) Put(outlet *pb.OutletDetails) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.outlets[outlet.OutletId]; !ok {
+		s.order = append(s.order, outlet.OutletId)
+	}
+	s.outlets[outlet.OutletId] = proto.Clone(outlet).(*pb.OutletDetails)
+	s.record(Change{Type: pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET, OutletID: outlet.OutletId})
+}
+
+// Delete removes an outlet, reporting whether it existed
+func (s *Store) Delete(outletID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.outlets[outletID]; !ok {
+		return false
+	}
+	delete(s.outlets, outletID)
+	for i, id := range s.order {
+		if id == outletID {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	s.record(Change{Type: pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET, OutletID: outletID, Deleted: true})
+	return true
+}
+
+// List returns copies of all outlets in insertion order
+func (s *Store) List() []*pb.OutletDetails {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	outlets := make([]*pb.OutletDetails, 0, len(s.order))
+	for _, id := range s.order {
+		outlets = append(outlets, proto.Clone(s.outlets[id]).(*pb.OutletDetails))
+	}
+	return outlets
+}
+
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.order)
+}
+
+// IDs returns the outlet IDs in insertion order
+func (s *Store) IDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.order...)
+}
+
+// Save writes a snapshot of the store to path. Files ending in .json are
+// written as protojson, everything else as binary protobuf.
+func (s *Store) Save(path string) error {
+	snapshot := &pb.OutletDetailsResponse{Details: s.List()}
+
+	var data []byte
+	var err error
+	if isJSON(path) {
+		data, err = protojson.MarshalOptions{Multiline: true}.Marshal(snapshot)
+	} else {
+		data, err = proto.Marshal(snapshot)
+	}
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	// Write to a temp file first so a crash mid-write never leaves a broken snapshot
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+	// CreateTemp uses 0600, but snapshots are meant to be shared
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load replaces the store contents with the snapshot at path
+func (s *Store) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+
+	snapshot := &pb.OutletDetailsResponse{}
+	if isJSON(path) {
+		err = protojson.Unmarshal(data, snapshot)
+	} else {
+		err = proto.Unmarshal(data, snapshot)
+	}
+	if err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.outlets = map[string]*pb.OutletDetails{}
+	s.order = nil
+	// Sync tokens and versions of the replaced contents no longer apply
+	s.epoch = time.Now().UnixNano()
+	s.revision = 0
+	s.changes = nil
+	s.versions = nil
+	for _, outlet := range snapshot.Details {
+		if _, ok := s.outlets[outlet.OutletId]; !ok {
+			s.order = append(s.order, outlet.OutletId)
+		}
+		s.outlets[outlet.OutletId] = outlet
+	}
+	return nil
+}
+
+func isJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// outletVersions tracks the revisions an outlet and its records last changed
+// at, for optimistic concurrency. Outlets restored from a snapshot start at 0.
+type outletVersions struct {
+	put     int64 // the outlet was last replaced as a whole, records without their own version date from here
+	outlet  int64 // the outlet or any of its records last changed
+	records map[recordKey]int64
+}
+
+type recordKey struct {
+	recordType pb.SyncRecordType
+	recordID   string
+}
+
+// bumpVersions moves the versions a change touches to its revision, the caller holds the lock
+func (s *Store) bumpVersions(change Change) {
+	if s.versions == nil {
+		s.versions = map[string]*outletVersions{}
+	}
+	if change.Type == pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET {
+		if change.Deleted {
+			delete(s.versions, change.OutletID)
+		} else {
+			s.versions[change.OutletID] = &outletVersions{put: change.Revision, outlet: change.Revision}
+		}
+		return
+	}
+
+	versions, ok := s.versions[change.OutletID]
+	if !ok {
+		versions = &outletVersions{}
+		s.versions[change.OutletID] = versions
+	}
+	if versions.records == nil {
+		versions.records = map[recordKey]int64{}
+	}
+	versions.outlet = change.Revision
+	key := recordKey{change.Type, change.RecordID}
+	if change.Deleted {
+		delete(versions.records, key)
+	} else {
+		versions.records[key] = change.Revision
+	}
+}
+
+// Versioned returns a copy of an outlet (recordType SYNC_RECORD_TYPE_OUTLET)
+// or one of its records together with its version. The version changes with
+// every change to the outlet or record, for outlets including their records.
+func (s *Store) Versioned(recordType pb.SyncRecordType, outletID, recordID string) (proto.Message, int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	outlet, ok := s.outlets[outletID]
+	if !ok {
+		return nil, 0, false
+	}
+	versions := s.versions[outletID]
+	if versions == nil {
+		versions = &outletVersions{}
+	}
+	if recordType == pb.SyncRecordType_SYNC_RECORD_TYPE_OUTLET {
+		return proto.Clone(outlet), versions.outlet, true
+	}
+
+	record, ok := findOutletRecord(outlet, recordType, recordID)
+	if !ok {
+		return nil, 0, false
+	}
+	version, ok := versions.records[recordKey{recordType, recordID}]
+	if !ok {
+		version = versions.put
+	}
+	return proto.Clone(record), version, true
+}
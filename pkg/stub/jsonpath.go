@@ -0,0 +1,70 @@
+package stub
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseJSONPath splits a path like $.items[0].id into object keys and array indexes
+func parseJSONPath(path string) ([]any, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("JSON path %q must start with $", path)
+	}
+
+	var steps []any
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("JSON path %q has an empty key", path)
+			}
+			steps = append(steps, key)
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("JSON path %q has an unclosed [", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("JSON path %q has an invalid index %q", path, rest[1:end])
+			}
+			steps = append(steps, index)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSON path %q is invalid at %q", path, rest)
+		}
+	}
+	return steps, nil
+}
+
+// lookup follows steps through a decoded JSON document
+func lookup(document any, steps []any) (any, bool) {
+	current := document
+	for _, step := range steps {
+		switch step := step.(type) {
+		case string:
+			object, ok := current.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if current, ok = object[step]; !ok {
+				return nil, false
+			}
+		case int:
+			array, ok := current.([]any)
+			if !ok || step >= len(array) {
+				return nil, false
+			}
+			current = array[step]
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,197 @@
+package stub
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"srv-eazle-advise-mock/pkg/fault"
+	pb "srv-eazle-advise-mock/pkg/gen/proto/outlet"
+	"srv-eazle-advise-mock/pkg/mock"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Response is what a mapping answers with. At most one body kind may be set:
+//   - body: a fixed text body
+//   - jsonBody: a fixed JSON body
+//   - base64Body: a fixed binary body
+//   - outlets: an OutletDetailsResponse in protojson, sent as protobuf or JSON depending on Accept
+//   - generate: generated outlets with the fields of overrides fixed
+//
+// Recordings set base64Body next to outlets: the raw body is sent as long as it
+// holds the same outlets in the format the request accepts, otherwise outlets
+// is encoded instead.
+type Response struct {
+	Status     int               `json:"status,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	JSONBody   json.RawMessage   `json:"jsonBody,omitempty"`
+	Base64Body string            `json:"base64Body,omitempty"`
+	Outlets    json.RawMessage   `json:"outlets,omitempty"`
+	Generate   *Generate         `json:"generate,omitempty"`
+}
+
+// Generate builds outlets with the generator, keeping every field set in Overrides
+type Generate struct {
+	Count     int             `json:"count,omitempty"`     // default 1
+	Overrides json.RawMessage `json:"overrides,omitempty"` // protojson of an OutletDetails
+}
+
+func (r Response) Validate() error {
+	kinds := 0
+	// A raw body may accompany outlets, see Response
+	raw := r.Base64Body != "" && len(r.Outlets) == 0
+	for _, set := range []bool{r.Body != "", len(r.JSONBody) > 0, raw, len(r.Outlets) > 0, r.Generate != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds > 1 {
+		return fmt.Errorf("response needs at most one of body, jsonBody, base64Body, outlets and generate")
+	}
+	if r.Status != 0 && (r.Status < 100 || r.Status > 599) {
+		return fmt.Errorf("invalid response status %d", r.Status)
+	}
+	if len(r.JSONBody) > 0 && !json.Valid(r.JSONBody) {
+		return fmt.Errorf("jsonBody is not valid JSON")
+	}
+	if r.Base64Body != "" {
+		if _, err := base64.StdEncoding.DecodeString(r.Base64Body); err != nil {
+			return fmt.Errorf("base64Body: %w", err)
+		}
+	}
+	if len(r.Outlets) > 0 {
+		if err := protojson.Unmarshal(r.Outlets, &pb.OutletDetailsResponse{}); err != nil {
+			return fmt.Errorf("outlets: %w", err)
+		}
+	}
+	if r.Generate != nil {
+		if r.Generate.Count < 0 {
+			return fmt.Errorf("generate count must not be negative")
+		}
+		if len(r.Generate.Overrides) > 0 {
+			if err := protojson.Unmarshal(r.Generate.Overrides, &pb.OutletDetails{}); err != nil {
+				return fmt.Errorf("generate overrides: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r Response) write(w http.ResponseWriter, req *http.Request, settings mock.MockSettings, networkFault fault.Rule) {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	headers := r.Headers
+	var data []byte
+	switch {
+	case len(r.JSONBody) > 0:
+		w.Header().Set("Content-Type", "application/json")
+		data = r.JSONBody
+	case len(r.Outlets) > 0:
+		outlets := &pb.OutletDetailsResponse{}
+		protojson.Unmarshal(r.Outlets, outlets)
+		if raw, ok := r.rawOutlets(req, outlets); ok {
+			data = raw
+			break
+		}
+		data = encodeOutlets(w, req, outlets)
+		if r.Base64Body != "" {
+			// A recorded Content-Type describes the raw body, not the edited outlets
+			headers = map[string]string{}
+			for name, value := range r.Headers {
+				if http.CanonicalHeaderKey(name) != "Content-Type" {
+					headers[name] = value
+				}
+			}
+		}
+	case r.Base64Body != "":
+		data, _ = base64.StdEncoding.DecodeString(r.Base64Body)
+	case r.Generate != nil:
+		if acceptLanguage := req.Header.Get("Accept-Language"); acceptLanguage != "" {
+			settings.Locale = mock.MatchLocale(acceptLanguage)
+		}
+		data = encodeOutlets(w, req, r.Generate.outlets(settings))
+	default:
+		data = []byte(r.Body)
+	}
+
+	// Headers of the mapping win over the defaults
+	for name, value := range headers {
+		w.Header().Set(name, value)
+	}
+	if networkFault.Mode != "" {
+		fault.WriteBody(w, req, networkFault, status, data)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// rawOutlets returns the raw body recorded next to outlets while it still
+// holds the same outlets in the format the request accepts
+func (r Response) rawOutlets(req *http.Request, outlets *pb.OutletDetailsResponse) ([]byte, bool) {
+	if r.Base64Body == "" {
+		return nil, false
+	}
+	data, _ := base64.StdEncoding.DecodeString(r.Base64Body)
+
+	var contentType string
+	for name, value := range r.Headers {
+		if http.CanonicalHeaderKey(name) == "Content-Type" {
+			contentType = value
+		}
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	protobuf := strings.Contains(mediaType, "protobuf")
+	if protobuf != (req.Header.Get("Accept") == "application/protobuf") {
+		return nil, false
+	}
+	recorded := &pb.OutletDetailsResponse{}
+	var err error
+	if protobuf {
+		err = proto.Unmarshal(data, recorded)
+	} else {
+		err = protojson.Unmarshal(data, recorded)
+	}
+	if err != nil || !proto.Equal(recorded, outlets) {
+		return nil, false
+	}
+	return data, true
+}
+
+func (g *Generate) outlets(settings mock.MockSettings) *pb.OutletDetailsResponse {
+	template := &pb.OutletDetails{}
+	protojson.Unmarshal(g.Overrides, template)
+
+	outlets := &pb.OutletDetailsResponse{}
+	for i := range max(g.Count, 1) {
+		outlet := proto.Clone(template).(*pb.OutletDetails)
+		if outlet.OutletId == "" {
+			outlet.OutletId = fmt.Sprintf("outlet-%03d", i+1)
+		}
+		outlets.Details = append(outlets.Details, mock.CompleteOutlet(outlet, settings))
+	}
+	return outlets
+}
+
+// encodeOutlets encodes as protobuf or JSON depending on the Accept header
+func encodeOutlets(w http.ResponseWriter, r *http.Request, outlets *pb.OutletDetailsResponse) []byte {
+	if r.Header.Get("Accept") == "application/protobuf" {
+		w.Header().Set("Content-Type", "application/protobuf")
+		data, _ := proto.Marshal(outlets)
+		return data
+	}
+	w.Header().Set("Content-Type", "application/json")
+	data, _ := protojson.Marshal(outlets)
+	return data
+}
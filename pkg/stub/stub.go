@@ -0,0 +1,290 @@
+package stub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"srv-eazle-advise-mock/pkg/fault"
+	"srv-eazle-advise-mock/pkg/mock"
+)
+
+// DefaultPriority is used for mappings without a priority, 1 is the highest
+const DefaultPriority = 5
+
+// Mapping returns a fixed response for the requests matching its pattern
+type Mapping struct {
+	ID       string         `json:"id,omitempty"`
+	Priority int            `json:"priority,omitempty"`
+	Request  RequestPattern `json:"request"`
+	Response Response       `json:"response"`
+}
+
+// RequestPattern matches requests, empty fields match everything
+type RequestPattern struct {
+	Method     string            `json:"method,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	PathPrefix string            `json:"pathPrefix,omitempty"`
+	Query      map[string]Values `json:"query,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"` // exact value, "" only requires presence
+	Body       []BodyPattern     `json:"bodyPatterns,omitempty"`
+}
+
+// Values are the values a query parameter must have, in JSON a single value
+// may be given as a plain string. A lone "" only requires presence.
+type Values []string
+
+func (v Values) MarshalJSON() ([]byte, error) {
+	if len(v) == 1 {
+		return json.Marshal(v[0])
+	}
+	return json.Marshal([]string(v))
+}
+
+func (v *Values) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = Values{value}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(v))
+}
+
+func (v Values) matches(values []string) bool {
+	if len(values) == 0 {
+		return false
+	}
+	for _, want := range v {
+		if want != "" && !contains(values, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// BodyPattern matches a JSON request body on the value at a path like
+// $.settings.locale or $.items[0].id. Without Equals the value only has to exist.
+type BodyPattern struct {
+	JSONPath string          `json:"jsonPath"`
+	Equals   json.RawMessage `json:"equals,omitempty"`
+}
+
+func (m Mapping) Validate() error {
+	if m.Request.Path == "" && m.Request.PathPrefix == "" {
+		return fmt.Errorf("mapping needs a request path or pathPrefix")
+	}
+	for _, pattern := range m.Request.Body {
+		if _, err := parseJSONPath(pattern.JSONPath); err != nil {
+			return err
+		}
+		if len(pattern.Equals) > 0 && !json.Valid(pattern.Equals) {
+			return fmt.Errorf("bodyPatterns %s: equals is not valid JSON", pattern.JSONPath)
+		}
+	}
+	return m.Response.Validate()
+}
+
+func (p RequestPattern) matches(r *http.Request, body []byte) bool {
+	if p.Method != "" && !strings.EqualFold(p.Method, r.Method) {
+		return false
+	}
+	if p.Path != "" && p.Path != r.URL.Path {
+		return false
+	}
+	if p.PathPrefix != "" && !strings.HasPrefix(r.URL.Path, p.PathPrefix) {
+		return false
+	}
+	query := r.URL.Query()
+	for name, want := range p.Query {
+		if !want.matches(query[name]) {
+			return false
+		}
+	}
+	for name, want := range p.Headers {
+		values := r.Header.Values(name)
+		if len(values) == 0 || (want != "" && !contains(values, want)) {
+			return false
+		}
+	}
+	if len(p.Body) == 0 {
+		return true
+	}
+
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return false
+	}
+	for _, pattern := range p.Body {
+		if !pattern.matches(document) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p BodyPattern) matches(document any) bool {
+	steps, err := parseJSONPath(p.JSONPath)
+	if err != nil {
+		return false
+	}
+	value, ok := lookup(document, steps)
+	if !ok {
+		return false
+	}
+	if len(p.Equals) == 0 {
+		return true
+	}
+	var want any
+	if err := json.Unmarshal(p.Equals, &want); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(value, want)
+}
+
+func contains(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Store holds the stub mappings, ordered by priority
+type Store struct {
+	mu       sync.RWMutex
+	mappings []Mapping
+	nextID   int
+	settings func() mock.MockSettings
+}
+
+// NewStore creates an empty store, settings provides the defaults for generated outlets
+func NewStore(settings func() mock.MockSettings) *Store {
+	return &Store{settings: settings}
+}
+
+// Add validates and stores a mapping, assigning an ID and priority when it has none
+func (s *Store) Add(mapping Mapping) (Mapping, error) {
+	if err := mapping.Validate(); err != nil {
+		return mapping, err
+	}
+	if mapping.Priority == 0 {
+		mapping.Priority = DefaultPriority
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	if mapping.ID == "" {
+		mapping.ID = fmt.Sprintf("mapping-%d", s.nextID)
+	}
+	for i, existing := range s.mappings {
+		if existing.ID == mapping.ID {
+			s.mappings = append(s.mappings[:i], s.mappings[i+1:]...)
+			break
+		}
+	}
+	// Among equal priorities the most recently added mapping wins
+	s.mappings = append([]Mapping{mapping}, s.mappings...)
+	sort.SliceStable(s.mappings, func(i, j int) bool { return s.mappings[i].Priority < s.mappings[j].Priority })
+	return mapping, nil
+}
+
+// Remove deletes the mapping with the given ID, reporting whether it existed
+func (s *Store) Remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, mapping := range s.mappings {
+		if mapping.ID == id {
+			s.mappings = append(s.mappings[:i], s.mappings[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mappings = nil
+}
+
+func (s *Store) Mappings() []Mapping {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Mapping{}, s.mappings...)
+}
+
+// LoadDir adds the mappings of every *.json file in dir. A file holds a single
+// mapping or an array of them.
+func (s *Store) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var mappings []Mapping
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(data, &mappings)
+		} else {
+			mappings = make([]Mapping, 1)
+			err = json.Unmarshal(data, &mappings[0])
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, mapping := range mappings {
+			if _, err := s.Add(mapping); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Match returns the highest priority mapping for the request. The body is read
+// for the body patterns and restored for the next handler. The admin API under
+// /__admin/ can't be stubbed.
+func (s *Store) Match(r *http.Request) (Mapping, bool) {
+	if strings.HasPrefix(r.URL.Path, "/__admin/") {
+		return Mapping{}, false
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, mapping := range s.mappings {
+		if mapping.Request.matches(r, body) {
+			return mapping, true
+		}
+	}
+	return Mapping{}, false
+}
+
+// Serve answers the request with the response of mapping, breaking the transport
+// as networkFault describes when its mode is set
+func (s *Store) Serve(w http.ResponseWriter, r *http.Request, mapping Mapping, networkFault fault.Rule) {
+	w.Header().Set("X-Stub-Mapping", mapping.ID)
+	mapping.Response.write(w, r, s.settings(), networkFault)
+}
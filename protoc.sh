@@ -1,6 +1,6 @@
 #!/bin/bash
 
-protoc --go_out=. --proto_path=. proto/*.proto
+protoc --go_out=. --go-grpc_out=. --proto_path=. proto/*.proto
 
 rm -rf pkg/gen/proto
 mv srv-eazle-advise-mock/proto pkg/gen/proto